@@ -1,12 +1,131 @@
 package exporter
 
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
 type DownloadResponse struct {
-	FileName    string
-	FileSize    int64
-	ContentType string
-	Content     []byte
+	FileName        string
+	FileSize        int64
+	ContentType     string
+	Content         []byte
+	ContentEncoding string // "gzip" when Content is gzip-compressed, see Gzip
 }
 
+// DefaultDataURIMaxSize is the content size DataURI accepts when no limit is given
+const DefaultDataURIMaxSize = 1 << 20
+
 type DataExporter interface {
 	Export(data any) (*DownloadResponse, error)
 }
+
+// WriteHTTP sends the file as an attachment, setting Content-Type,
+// Content-Length and a Content-Disposition that keeps non-ASCII names intact
+func (r *DownloadResponse) WriteHTTP(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", r.ContentType)
+	w.Header().Set("Content-Length", strconv.Itoa(len(r.Content)))
+	w.Header().Set("Content-Disposition", r.ContentDispositionHeader())
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(r.Content); err != nil {
+		return fmt.Errorf("write response failed: %v", err)
+	}
+	return nil
+}
+
+// ServeHTTP makes a response usable as an http.Handler, see WriteHTTP
+func (r *DownloadResponse) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	_ = r.WriteHTTP(w)
+}
+
+// SHA256 returns the hex-encoded SHA-256 digest of the content
+func (r *DownloadResponse) SHA256() string {
+	return r.Checksum(sha256.New)
+}
+
+// Checksum returns the hex-encoded digest of the content for any hash, e.g. Checksum(sha512.New)
+func (r *DownloadResponse) Checksum(newHash func() hash.Hash) string {
+	h := newHash()
+	h.Write(r.Content)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// DataURI returns the content base64-encoded as a data: URI for inlining small
+// files. It fails when the content exceeds maxSize bytes, 0 meaning DefaultDataURIMaxSize.
+func (r *DownloadResponse) DataURI(maxSize int64) (string, error) {
+	if maxSize <= 0 {
+		maxSize = DefaultDataURIMaxSize
+	}
+	if int64(len(r.Content)) > maxSize {
+		return "", fmt.Errorf("content is %d bytes, exceeds data URI limit %d", len(r.Content), maxSize)
+	}
+	return "data:" + r.ContentType + ";base64," + base64.StdEncoding.EncodeToString(r.Content), nil
+}
+
+// Gzip returns a gzip-compressed copy of the response named FileName + ".gz".
+// xlsx files are zip archives already, so expect only a few percent saving; it
+// is meant for consumers that require gzip transport. WriteHTTP serves the copy
+// as an application/gzip download. To have clients decompress it transparently
+// instead, send ContentEncoding as the Content-Encoding header together with the
+// original file's name and content type.
+func (r *DownloadResponse) Gzip() (*DownloadResponse, error) {
+	var buffer bytes.Buffer
+	zw := gzip.NewWriter(&buffer)
+	if _, err := zw.Write(r.Content); err != nil {
+		return nil, fmt.Errorf("gzip failed: %v", err)
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("gzip failed: %v", err)
+	}
+
+	content := buffer.Bytes()
+	return &DownloadResponse{
+		FileName:        r.FileName + ".gz",
+		FileSize:        int64(len(content)),
+		ContentType:     "application/gzip",
+		Content:         content,
+		ContentEncoding: "gzip",
+	}, nil
+}
+
+// ContentDispositionHeader returns an attachment Content-Disposition value for
+// FileName with an ASCII fallback name for old clients and the RFC 5987 encoded
+// UTF-8 name, e.g. for "报表.xlsx":
+//
+//	attachment; filename="__.xlsx"; filename*=UTF-8''%E6%8A%A5%E8%A1%A8.xlsx
+func (r *DownloadResponse) ContentDispositionHeader() string {
+	fileName := r.FileName
+	var fallback, encoded strings.Builder
+	for _, c := range fileName {
+		if c < 0x20 || c > 0x7e || c == '"' || c == '\\' {
+			fallback.WriteByte('_')
+		} else {
+			fallback.WriteRune(c)
+		}
+	}
+	for _, b := range []byte(fileName) {
+		if isAttrChar(b) {
+			encoded.WriteByte(b)
+		} else {
+			fmt.Fprintf(&encoded, "%%%02X", b)
+		}
+	}
+	return fmt.Sprintf(`attachment; filename="%s"; filename*=UTF-8''%s`, fallback.String(), encoded.String())
+}
+
+// isAttrChar reports whether b may appear unescaped in an RFC 5987 value
+func isAttrChar(b byte) bool {
+	switch {
+	case 'a' <= b && b <= 'z', 'A' <= b && b <= 'Z', '0' <= b && b <= '9':
+		return true
+	}
+	return strings.IndexByte("!#$&+-.^_`|~", b) >= 0
+}
@@ -0,0 +1,69 @@
+package exporter
+
+import "time"
+
+// EventKind identifies a point in the export lifecycle reported to OnEvent
+type EventKind int
+
+const (
+	// EventHeaderResolved fires before the data rows of a sheet are written, with the final columns
+	EventHeaderResolved EventKind = iota
+	// EventRowsProcessed fires every ProgressInterval data rows
+	EventRowsProcessed
+	// EventRowError fires for the row that failed conversion or writing, which aborts the export
+	EventRowError
+	// EventCompleted fires once the data rows of a sheet are written, with Err set if the export was aborted
+	EventCompleted
+)
+
+func (k EventKind) String() string {
+	switch k {
+	case EventHeaderResolved:
+		return "header_resolved"
+	case EventRowsProcessed:
+		return "rows_processed"
+	case EventRowError:
+		return "row_error"
+	case EventCompleted:
+		return "completed"
+	}
+	return "unknown"
+}
+
+// Event is an observability record passed to OnEvent
+type Event struct {
+	Kind     EventKind
+	Sheet    string        // Sheet being written, for ExportFormats the workbook sheet
+	RowIndex int           // Sheet row the event refers to, 0 if none
+	Rows     int           // Data rows written so far
+	Columns  []string      // Headers, for EventHeaderResolved
+	Err      error         // Row error for EventRowError, fatal error for EventCompleted
+	Elapsed  time.Duration // Time since the first data row started
+}
+
+// exportStats tracks one pass over the data rows for events
+type exportStats struct {
+	sheet     string
+	started   time.Time
+	processed int
+}
+
+// newExportStats starts the clock only when someone is listening
+func (e *ExcelExporter[T]) newExportStats(sheet string) exportStats {
+	if e.config.OnEvent == nil {
+		return exportStats{}
+	}
+	return exportStats{sheet: sheet, started: time.Now()}
+}
+
+func (e *ExcelExporter[T]) emit(ev Event, stats exportStats) {
+	if e.config.OnEvent == nil {
+		return
+	}
+	ev.Sheet = stats.sheet
+	ev.Rows = stats.processed
+	if !stats.started.IsZero() {
+		ev.Elapsed = time.Since(stats.started)
+	}
+	e.config.OnEvent(ev)
+}
@@ -2,8 +2,15 @@ package exporter
 
 import (
 	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"maps"
+	"math"
 	"reflect"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -11,21 +18,116 @@ import (
 	"github.com/xuri/excelize/v2"
 )
 
+// EmptyDataPolicy controls what Export produces when there is no data
+type EmptyDataPolicy int
+
+const (
+	// EmptyDataWriteHeadersOnly writes a file with the header row only (default)
+	EmptyDataWriteHeadersOnly EmptyDataPolicy = iota
+	// EmptyDataError returns an error instead of a file
+	EmptyDataError
+	// EmptyDataWriteNothing writes a file with a blank sheet
+	EmptyDataWriteNothing
+)
+
+// NonFinitePolicy controls how NaN and infinite float values are written
+type NonFinitePolicy int
+
+const (
+	// NonFiniteBlank leaves the cell empty (default)
+	NonFiniteBlank NonFinitePolicy = iota
+	// NonFiniteText writes "NaN", "+Inf" or "-Inf" as text
+	NonFiniteText
+	// NonFiniteError fails the export
+	NonFiniteError
+)
+
+// ColumnWidthAuto sizes a column to fit its longest value, for ColumnWidths,
+// DefaultColumnWidth or the tag option "width:auto"
+const ColumnWidthAuto = -1.0
+
+// ValidationRule is a dropdown list applied to a row range of one column
+type ValidationRule struct {
+	FromRow int // First sheet row (1-based), 0 means the first data row
+	ToRow   int // Last sheet row, 0 means the last row covered by ValidationRows
+	Options []string
+}
+
+// LegendEntry describes one column on the legend sheet
+type LegendEntry struct {
+	Header      string
+	Description string
+	Example     string
+}
+
+// ComputedColumn is a column whose value is derived from the whole row rather than
+// read from a field, e.g. a full name built from first and last name
+type ComputedColumn[T any] struct {
+	Header string
+	Value  func(T) any
+}
+
 // ExcelExportConfig configuration for Excel export
 type ExcelExportConfig[T any] struct {
-	FileName         string
-	SheetName        string
-	Headers          []string
-	Dropdowns        map[int][]string
-	CustomConverters map[string]func(any) any
-	TextColumns      map[string]bool
-	ColumnWidths     map[string]float64
+	FileName           string
+	SheetName          string
+	Headers            []string
+	Dropdowns          map[int][]string
+	EnumValues         map[string][]string // Header -> allowed values, added as a dropdown unless Dropdowns or Validations cover the column; same as tag "enum:启用|停用"
+	CustomConverters   map[string]func(any) any
+	TextColumns        map[string]bool
+	DateColumns        map[string]string // Header -> Excel number format such as "yyyy-mm-dd", time.Time values are written as real dates; same as tag "date:yyyy-mm-dd"
+	NumberFormats      map[string]string // Header -> number format of numeric cells, e.g. "#,##0.00"; same as tag "numfmt:0.00"
+	InferNumberFormats bool              // Format numeric columns missing from NumberFormats by value: integers as "0", floats as FloatFormat
+	FloatFormat        string            // Number format inferred for floats, defaults to "0.00"; columns with FloatPrecision show that many decimals
+	ColumnWidths       map[string]float64
+	Alignments         map[string]excelize.Alignment // Header -> data cell alignment, same as tag "align:right" or "align:center|top"; see setColumnStyles
+	ZebraStriping      bool
+	ZebraColors        []string                                     // Fill colors alternated across data rows, "" means no fill. Defaults to {"", "F2F2F2"}
+	ConditionalFormats map[string]excelize.ConditionalFormatOptions // Header -> rule such as a data bar or color scale, applied over the column's data rows
+	OnEmptyData        EmptyDataPolicy
+	HeaderStyle        *excelize.Style          // nil uses the default look, an empty Style disables header styling
+	PrintTitleRows     bool                     // Repeat the header row on every printed page
+	FreezeHeader       bool                     // Keep the header row visible when scrolling down
+	FreezeFirstColumn  bool                     // Keep the first column visible when scrolling right, e.g. row labels or IDs
+	ShowGridLines      *bool                    // Sheet gridlines, nil keeps Excel's default of shown
+	ShowRowColHeaders  *bool                    // Row numbers and column letters, nil keeps Excel's default of shown
+	RightToLeft        bool                     // Lay the sheet out right to left for Arabic or Hebrew reports; column A is then rightmost
+	Location           *time.Location           // Zone time.Time values are rendered in, nil keeps each value's own zone
+	ValidationRows     int                      // Number of data rows dropdown validations cover, defaults to 999 (sheet rows 2-1000)
+	Validations        map[int][]ValidationRule // Column index -> dropdown rules, for several ranges per column
+	FloatPrecision     map[string]int           // Header -> decimal places float values are rounded to, same as tag "round:N"
+	JSONColumns        map[string]bool          // Headers whose struct/map/slice values are written as JSON, same as tag "json"
+	DefaultColumnWidth float64                  // Width for columns without an explicit width, defaults to 15
+	BoolValues         [2]string                // Cell text for true and false, e.g. {"是", "否"}; empty keeps TRUE/FALSE
+	BoolColumns        map[string][2]string     // Header -> true/false text, same as tag "bool:是|否"
+	ColumnFilter       func(header string) bool // Evaluated on every export, columns it rejects are left out entirely
+	OnNonFinite        NonFinitePolicy          // NaN and ±Inf would corrupt the cell, so they are never written as numbers
+	DocProps           *excelize.DocProperties  // Core properties such as Creator, Title, Subject and Keywords
+	AppProps           *excelize.AppProperties  // Application properties such as Company and Manager
+	GroupBy            func(T) string           // Consecutive rows with the same key form a group, see GroupTitle
+	GroupTitle         func(key string) string  // Text of a title row above each group, nil leaves a blank row between groups
+	ComputedColumns    []ComputedColumn[T]      // Appended after the field columns unless Headers already places them
+	OutlineLevels      map[string]uint8         // Header -> column outline level 1-7, adjacent columns of a level form a collapsible group
+	CollapseOutlines   bool                     // Hide the outlined columns so the sheet opens with its groups collapsed
+	Legend             []LegendEntry            // Column reference written to a sheet after the data sheet, nil adds no sheet
+	LegendSheetName    string                   // Name of the legend sheet, defaults to "Instructions"
+	ProgressInterval   int                      // Data rows between EventRowsProcessed events, defaults to 100
+	OnEvent            func(Event)              // Lifecycle hook for logging and metrics, nil disables it
+	ExtraColumns       bool                     // Write the keys of the map field tagged excel:"extra" as sorted columns after the tagged ones
 }
 
 // ExcelExporter generic exporter
 type ExcelExporter[T any] struct {
-	config   *ExcelExportConfig[T]
-	fieldMap map[string]string // Header -> FieldName
+	config       *ExcelExportConfig[T]
+	fieldMap     map[string]string // Header -> FieldName
+	isMap        bool              // T is a map keyed by header, e.g. map[string]any
+	columnKinds  map[string]columnKind
+	numFmtStyles map[numFmtStyleKey]int // Number format style IDs, per export
+	// dynamicField is the map field tagged excel:"extra" whose keys become extra columns, see ExtraColumns
+	dynamicField    string
+	headersInferred bool
+	computed        map[string]func(T) any // Header -> ComputedColumn value
 }
 
 // NewExcelExporter creates a new exporter instance
@@ -42,21 +144,142 @@ func NewExcelExporter[T any](config *ExcelExportConfig[T]) *ExcelExporter[T] {
 	if config.TextColumns == nil {
 		config.TextColumns = make(map[string]bool)
 	}
+	if config.DateColumns == nil {
+		config.DateColumns = make(map[string]string)
+	}
+	if config.NumberFormats == nil {
+		config.NumberFormats = make(map[string]string)
+	}
+	if config.EnumValues == nil {
+		config.EnumValues = make(map[string][]string)
+	}
+	if config.FloatFormat == "" {
+		config.FloatFormat = "0.00"
+	}
 	if config.ColumnWidths == nil {
 		config.ColumnWidths = make(map[string]float64)
 	}
+	if config.Alignments == nil {
+		config.Alignments = make(map[string]excelize.Alignment)
+	}
+	if config.FloatPrecision == nil {
+		config.FloatPrecision = make(map[string]int)
+	}
+	if config.JSONColumns == nil {
+		config.JSONColumns = make(map[string]bool)
+	}
+	if config.BoolColumns == nil {
+		config.BoolColumns = make(map[string][2]string)
+	}
+	if config.DefaultColumnWidth == 0 {
+		config.DefaultColumnWidth = 15
+	}
+	if config.ValidationRows == 0 {
+		config.ValidationRows = 999
+	}
+	if config.ProgressInterval <= 0 {
+		config.ProgressInterval = 100
+	}
+	if config.LegendSheetName == "" {
+		config.LegendSheetName = "Instructions"
+	}
 
 	exporter := &ExcelExporter[T]{config: config}
 	exporter.parseTags()
+	if !exporter.isMap || len(config.Headers) > 0 {
+		config.Headers = exporter.appendComputed(config.Headers)
+	}
 	return exporter
 }
 
+// NewExcelExporterE is NewExcelExporter that also rejects configurations which
+// would otherwise only fail, or silently do nothing, at export time
+func NewExcelExporterE[T any](config *ExcelExportConfig[T]) (*ExcelExporter[T], error) {
+	exporter := NewExcelExporter(config)
+	if err := exporter.checkStrict(); err != nil {
+		return nil, err
+	}
+	return exporter, nil
+}
+
+// checkStrict verifies the row type and that headers and per-column options
+// refer to columns the exporter can fill
+func (e *ExcelExporter[T]) checkStrict() error {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if e.isMap {
+		return nil
+	}
+	if t.Kind() != reflect.Struct {
+		return fmt.Errorf("invalid config: row type %s is not a struct or a map with string keys", t)
+	}
+	if len(e.fieldMap) == 0 && e.dynamicField == "" {
+		return fmt.Errorf("invalid config: %s has no excel tags", t)
+	}
+
+	var errs []error
+	if e.dynamicField != "" {
+		field, _ := t.FieldByName(e.dynamicField)
+		if field.Type.Kind() != reflect.Map || field.Type.Key().Kind() != reflect.String {
+			errs = append(errs, fmt.Errorf("invalid config: dynamic field %s must be a map with string keys, got %s", field.Name, field.Type))
+		}
+	}
+
+	// Dynamic map keys are only known at export time
+	known := func(header string) bool {
+		if _, ok := e.computed[header]; ok || e.dynamicField != "" {
+			return true
+		}
+		_, ok := e.fieldMap[header]
+		if !ok {
+			_, ok = e.fieldMap[normalizeHeader(header)]
+		}
+		return ok
+	}
+	for _, header := range e.config.Headers {
+		if !known(header) {
+			errs = append(errs, fmt.Errorf("invalid config: header %s has no matching field", header))
+		}
+	}
+
+	fieldNames := make(map[string]bool, len(e.fieldMap))
+	for _, fieldName := range e.fieldMap {
+		fieldNames[fieldName] = true
+	}
+	converters := slices.Sorted(maps.Keys(e.config.CustomConverters))
+	for _, key := range converters {
+		if !fieldNames[key] && !known(key) {
+			errs = append(errs, fmt.Errorf("invalid config: CustomConverters references unknown field or header %s", key))
+		}
+	}
+
+	for _, option := range []struct {
+		name    string
+		columns []int
+	}{
+		{"Dropdowns", slices.Sorted(maps.Keys(e.config.Dropdowns))},
+		{"Validations", slices.Sorted(maps.Keys(e.config.Validations))},
+	} {
+		for _, colIndex := range option.columns {
+			if colIndex < 0 || colIndex >= len(e.config.Headers) {
+				errs = append(errs, fmt.Errorf("invalid config: %s column %d is out of range for %d headers", option.name, colIndex, len(e.config.Headers)))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
 func (e *ExcelExporter[T]) parseTags() {
-	var zero T
-	t := reflect.TypeOf(zero)
+	t := reflect.TypeOf((*T)(nil)).Elem()
 	if t.Kind() == reflect.Ptr {
 		t = t.Elem()
 	}
+	if t.Kind() == reflect.Map && t.Key().Kind() == reflect.String {
+		e.isMap = true
+		return
+	}
 	if t.Kind() != reflect.Struct {
 		return
 	}
@@ -73,6 +296,10 @@ func (e *ExcelExporter[T]) parseTags() {
 
 		parts := strings.Split(tag, ",")
 		headerName := strings.TrimSpace(parts[0])
+		if e.config.ExtraColumns && (headerName == "*" || headerName == "extra") {
+			e.dynamicField = field.Name
+			continue
+		}
 		e.fieldMap[headerName] = field.Name
 		inferredHeaders = append(inferredHeaders, headerName)
 
@@ -80,11 +307,39 @@ func (e *ExcelExporter[T]) parseTags() {
 			opt = strings.TrimSpace(opt)
 			if opt == "text" {
 				e.config.TextColumns[headerName] = true
+			} else if opt == "date" {
+				e.config.DateColumns[headerName] = ""
+			} else if strings.HasPrefix(opt, "date:") {
+				e.config.DateColumns[headerName] = strings.TrimPrefix(opt, "date:")
+			} else if strings.HasPrefix(opt, "numfmt:") {
+				e.config.NumberFormats[headerName] = strings.TrimPrefix(opt, "numfmt:")
+			} else if strings.HasPrefix(opt, "enum:") {
+				e.config.EnumValues[headerName] = strings.Split(strings.TrimPrefix(opt, "enum:"), "|")
+			} else if opt == "json" {
+				e.config.JSONColumns[headerName] = true
 			} else if strings.HasPrefix(opt, "width:") {
 				valStr := strings.TrimPrefix(opt, "width:")
-				if width, err := strconv.ParseFloat(valStr, 64); err == nil {
+				if valStr == "auto" {
+					e.config.ColumnWidths[headerName] = ColumnWidthAuto
+				} else if width, err := strconv.ParseFloat(valStr, 64); err == nil {
 					e.config.ColumnWidths[headerName] = width
 				}
+			} else if strings.HasPrefix(opt, "align:") {
+				parts := strings.SplitN(strings.TrimPrefix(opt, "align:"), "|", 2)
+				alignment := excelize.Alignment{Horizontal: parts[0]}
+				if len(parts) == 2 {
+					alignment.Vertical = parts[1]
+				}
+				e.config.Alignments[headerName] = alignment
+			} else if strings.HasPrefix(opt, "round:") {
+				valStr := strings.TrimPrefix(opt, "round:")
+				if places, err := strconv.Atoi(valStr); err == nil {
+					e.config.FloatPrecision[headerName] = places
+				}
+			} else if strings.HasPrefix(opt, "bool:") {
+				if labels := strings.SplitN(strings.TrimPrefix(opt, "bool:"), "|", 2); len(labels) == 2 {
+					e.config.BoolColumns[headerName] = [2]string{labels[0], labels[1]}
+				}
 			}
 		}
 	}
@@ -92,39 +347,210 @@ func (e *ExcelExporter[T]) parseTags() {
 	// Only use inferred headers if config headers are empty
 	if len(e.config.Headers) == 0 {
 		e.config.Headers = inferredHeaders
+		e.headersInferred = true
 	}
 }
 
 func (e *ExcelExporter[T]) Export(data []T) (*DownloadResponse, error) {
-	f := excelize.NewFile()
-	sheetName := e.config.SheetName
-	index, _ := f.GetSheetIndex("Sheet1")
-	if index != -1 {
-		_ = f.SetSheetName("Sheet1", sheetName)
-	}
-	if err := e.setHeaders(f, sheetName); err != nil {
+	return e.ExportContext(context.Background(), data)
+}
+
+// ExportGzip is Export with the file gzip-compressed, see DownloadResponse.Gzip
+func (e *ExcelExporter[T]) ExportGzip(data []T) (*DownloadResponse, error) {
+	resp, err := e.Export(data)
+	if err != nil {
 		return nil, err
 	}
+	return resp.Gzip()
+}
 
-	if err := e.setDropdownValidations(f, sheetName); err != nil {
+// ExportContext is Export with cancellation: ctx is checked between rows and
+// before the workbook is serialized, returning ctx.Err() once it is done.
+func (e *ExcelExporter[T]) ExportContext(ctx context.Context, data []T) (*DownloadResponse, error) {
+	if len(data) == 0 && e.config.OnEmptyData == EmptyDataError {
+		return nil, fmt.Errorf("no data to export")
+	}
+
+	e = e.prepare(data)
+	f, sheetName, err := e.newFile()
+	if err != nil {
 		return nil, err
 	}
 
-	if err := e.fillData(f, sheetName, data); err != nil {
+	if len(data) > 0 || e.config.OnEmptyData != EmptyDataWriteNothing {
+		if err := e.buildSheet(ctx, f, sheetName, data); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
 		return nil, err
 	}
 
-	if err := e.setTextColumnStyle(f, sheetName); err != nil {
+	return e.writeResponse(f)
+}
+
+// ExportOnto writes the data sheet into a workbook the caller owns, e.g. one
+// holding other sheets, and leaves writing the file to the caller. The sheet is
+// created when missing, taking over the blank "Sheet1" of a fresh workbook, and
+// written over otherwise; an empty sheetName uses SheetName. Legend, DocProps and AppProps describe the whole workbook and are
+// not applied.
+func (e *ExcelExporter[T]) ExportOnto(f *excelize.File, sheetName string, data []T) error {
+	if len(data) == 0 && e.config.OnEmptyData == EmptyDataError {
+		return fmt.Errorf("no data to export")
+	}
+	if sheetName == "" {
+		sheetName = e.config.SheetName
+	}
+	if err := claimSheet(f, sheetName); err != nil {
+		return fmt.Errorf("add sheet %s failed: %v", sheetName, err)
+	}
+
+	if len(data) == 0 && e.config.OnEmptyData == EmptyDataWriteNothing {
+		return nil
+	}
+	return e.prepare(data).buildSheet(context.Background(), f, sheetName, data)
+}
+
+// ExportTemplate produces an empty, fully styled template: headers, dropdowns,
+// text columns and widths, with validations covering ValidationRows rows.
+// It ignores OnEmptyData.
+func (e *ExcelExporter[T]) ExportTemplate() (*DownloadResponse, error) {
+	e = e.prepare(nil)
+	f, sheetName, err := e.newFile()
+	if err != nil {
 		return nil, err
 	}
 
-	if err := e.setHeaderStyle(f, sheetName); err != nil {
+	if err := e.buildSheet(context.Background(), f, sheetName, nil); err != nil {
 		return nil, err
 	}
 
-	if err := e.setColumnWidths(f, sheetName); err != nil {
+	return e.writeResponse(f)
+}
+
+// prepare returns a per-call copy of the exporter. For map data, headers missing
+// from the config are taken from the sorted map keys and column types are inferred.
+// ColumnFilter is applied last.
+func (e *ExcelExporter[T]) prepare(data []T) *ExcelExporter[T] {
+	call := *e
+	if e.dynamicField != "" && e.headersInferred {
+		config := *e.config
+		config.Headers = append(append([]string(nil), config.Headers...), e.dynamicKeys(data)...)
+		call.config = &config
+	}
+	if e.isMap {
+		config := *e.config
+		if len(config.Headers) == 0 {
+			rows := make([]reflect.Value, len(data))
+			for i, item := range data {
+				rows[i] = reflect.ValueOf(item)
+			}
+			config.Headers = call.appendComputed(mapKeys(rows))
+		}
+		call.config = &config
+		call.columnKinds = inferColumnKinds(data, config.Headers)
+	}
+	call.filterColumns()
+	return &call
+}
+
+// appendComputed registers the computed columns and adds the headers missing from headers
+func (e *ExcelExporter[T]) appendComputed(headers []string) []string {
+	if len(e.config.ComputedColumns) == 0 {
+		return headers
+	}
+	if e.computed == nil {
+		e.computed = make(map[string]func(T) any, len(e.config.ComputedColumns))
+	}
+	for _, column := range e.config.ComputedColumns {
+		e.computed[column.Header] = column.Value
+		if !slices.Contains(headers, column.Header) {
+			headers = append(headers, column.Header)
+		}
+	}
+	return headers
+}
+
+// filterColumns drops the headers rejected by ColumnFilter and moves the
+// index-keyed Dropdowns and Validations along with the remaining columns
+func (e *ExcelExporter[T]) filterColumns() {
+	if e.config.ColumnFilter == nil {
+		return
+	}
+
+	config := *e.config
+	config.Headers = nil
+	positions := make(map[int]int)
+	for colIndex, header := range e.config.Headers {
+		if e.config.ColumnFilter(header) {
+			positions[colIndex] = len(config.Headers)
+			config.Headers = append(config.Headers, header)
+		}
+	}
+	config.Dropdowns = remapColumns(config.Dropdowns, positions)
+	config.Validations = remapColumns(config.Validations, positions)
+	e.config = &config
+}
+
+func remapColumns[V any](byColumn map[int]V, positions map[int]int) map[int]V {
+	if byColumn == nil {
+		return nil
+	}
+	remapped := make(map[int]V, len(byColumn))
+	for colIndex, value := range byColumn {
+		if newIndex, ok := positions[colIndex]; ok {
+			remapped[newIndex] = value
+		}
+	}
+	return remapped
+}
+
+// defaultSheetName is the sheet excelize.NewFile starts with
+const defaultSheetName = "Sheet1"
+
+func (e *ExcelExporter[T]) newFile() (*excelize.File, string, error) {
+	f := excelize.NewFile()
+	sheetName := e.config.SheetName
+	if err := claimSheet(f, sheetName); err != nil {
+		return nil, "", fmt.Errorf("invalid sheet name %s: %v", sheetName, err)
+	}
+	return f, sheetName, nil
+}
+
+// claimSheet makes sheetName available for writing. A workbook holding nothing
+// but the blank default sheet has that sheet renamed, so no empty "Sheet1" is
+// left behind; otherwise a missing sheet is added and an existing one reused.
+func claimSheet(f *excelize.File, sheetName string) error {
+	if sheets := f.GetSheetList(); len(sheets) == 1 && sheets[0] == defaultSheetName && sheetName != defaultSheetName {
+		if rows, err := f.GetRows(defaultSheetName); err == nil && len(rows) == 0 {
+			return f.SetSheetName(defaultSheetName, sheetName)
+		}
+	}
+	index, err := f.GetSheetIndex(sheetName)
+	if err != nil {
+		return err
+	}
+	if index == -1 {
+		_, err = f.NewSheet(sheetName)
+	}
+	return err
+}
+
+func (e *ExcelExporter[T]) writeResponse(f *excelize.File) (*DownloadResponse, error) {
+	if err := e.addLegendSheet(f); err != nil {
 		return nil, err
 	}
+	if e.config.DocProps != nil {
+		if err := f.SetDocProps(e.config.DocProps); err != nil {
+			return nil, fmt.Errorf("set doc props failed: %v", err)
+		}
+	}
+	if e.config.AppProps != nil {
+		if err := f.SetAppProps(e.config.AppProps); err != nil {
+			return nil, fmt.Errorf("set app props failed: %v", err)
+		}
+	}
 
 	var buffer bytes.Buffer
 	if err := f.Write(&buffer); err != nil {
@@ -143,6 +569,84 @@ func (e *ExcelExporter[T]) Export(data []T) (*DownloadResponse, error) {
 	return response, nil
 }
 
+func (e *ExcelExporter[T]) buildSheet(ctx context.Context, f *excelize.File, sheetName string, data []T) error {
+	if err := e.buildSkeleton(f, sheetName); err != nil {
+		return err
+	}
+	return e.fillSheet(ctx, f, sheetName, data)
+}
+
+// buildSkeleton writes everything that does not depend on the data: headers,
+// validations and styles
+func (e *ExcelExporter[T]) buildSkeleton(f *excelize.File, sheetName string) error {
+	if err := e.setHeaders(f, sheetName); err != nil {
+		return err
+	}
+
+	if err := e.setDropdownValidations(f, sheetName); err != nil {
+		return err
+	}
+
+	if err := e.setColumnStyles(f, sheetName); err != nil {
+		return err
+	}
+
+	if err := e.setHeaderStyle(f, sheetName); err != nil {
+		return err
+	}
+
+	if err := e.setPrintTitles(f, sheetName); err != nil {
+		return err
+	}
+
+	if err := e.setPanes(f, sheetName); err != nil {
+		return err
+	}
+
+	if err := e.setSheetView(f, sheetName); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// fillSheet writes the data rows and the formatting that depends on them
+func (e *ExcelExporter[T]) fillSheet(ctx context.Context, f *excelize.File, sheetName string, data []T) error {
+	layout := e.layoutRows(data)
+	err := e.eachRow(ctx, sheetName, data, layout.dataRows, func(row int, cells []exportCell) error {
+		return e.writeRow(f, sheetName, row, cells)
+	})
+	if err != nil {
+		return err
+	}
+	return e.formatSheet(f, sheetName, layout)
+}
+
+// formatSheet writes the formatting that depends on the written data rows
+func (e *ExcelExporter[T]) formatSheet(f *excelize.File, sheetName string, layout rowLayout) error {
+	if err := e.setGroupTitles(f, sheetName, layout.titleRows); err != nil {
+		return err
+	}
+
+	if err := e.setZebraStriping(f, sheetName, layout.dataRows); err != nil {
+		return err
+	}
+
+	if err := e.setColumnWidths(f, sheetName); err != nil {
+		return err
+	}
+
+	if err := e.setColumnOutlines(f, sheetName); err != nil {
+		return err
+	}
+
+	if err := e.setConditionalFormats(f, sheetName, layout.dataRows); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func (e *ExcelExporter[T]) setHeaders(f *excelize.File, sheetName string) error {
 	for col, header := range e.config.Headers {
 		cell, err := excelize.CoordinatesToCellName(col+1, 1)
@@ -157,67 +661,173 @@ func (e *ExcelExporter[T]) setHeaders(f *excelize.File, sheetName string) error
 }
 
 func (e *ExcelExporter[T]) setDropdownValidations(f *excelize.File, sheetName string) error {
-	if e.config.Dropdowns == nil {
+	for colIndex, options := range e.config.Dropdowns {
+		if err := e.addDropdown(f, sheetName, colIndex, ValidationRule{Options: options}); err != nil {
+			return err
+		}
+	}
+
+	for colIndex, rules := range e.config.Validations {
+		for _, rule := range rules {
+			if err := e.addDropdown(f, sheetName, colIndex, rule); err != nil {
+				return err
+			}
+		}
+	}
+
+	for colIndex, header := range e.config.Headers {
+		options, ok := e.config.EnumValues[header]
+		if !ok || e.config.Dropdowns[colIndex] != nil || e.config.Validations[colIndex] != nil {
+			continue
+		}
+		if err := e.addDropdown(f, sheetName, colIndex, ValidationRule{Options: options}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (e *ExcelExporter[T]) addDropdown(f *excelize.File, sheetName string, colIndex int, rule ValidationRule) error {
+	if len(rule.Options) == 0 {
 		return nil
 	}
 
-	for colIndex, options := range e.config.Dropdowns {
-		if len(options) == 0 {
+	colName, err := excelize.ColumnNumberToName(colIndex + 1)
+	if err != nil {
+		return err
+	}
+
+	fromRow, toRow := rule.FromRow, rule.ToRow
+	if fromRow == 0 {
+		fromRow = 2
+	}
+	if toRow == 0 {
+		toRow = e.config.ValidationRows + 1
+	}
+	if toRow < fromRow {
+		return fmt.Errorf("invalid validation range for column %s: %d-%d", colName, fromRow, toRow)
+	}
+
+	dvRange := excelize.NewDataValidation(true)
+	dvRange.SetSqref(fmt.Sprintf("%s%d:%s%d", colName, fromRow, colName, toRow))
+	_ = dvRange.SetDropList(rule.Options)
+	title := "Error"
+	msg := "Invalid input"
+	dvRange.SetError(excelize.DataValidationErrorStyleWarning, title, msg)
+
+	return f.AddDataValidation(sheetName, dvRange)
+}
+
+// columnAlignment returns the configured alignment of a column. Text columns
+// default to aligned at the start of the line, left or right on RightToLeft
+// sheets; other columns keep Excel's alignment by value type.
+func (e *ExcelExporter[T]) columnAlignment(header string) *excelize.Alignment {
+	if alignment, ok := e.config.Alignments[header]; ok {
+		return &alignment
+	}
+	if e.config.TextColumns[header] {
+		horizontal := "left"
+		if e.config.RightToLeft {
+			horizontal = "right"
+		}
+		return &excelize.Alignment{Horizontal: horizontal, Vertical: "center"}
+	}
+	return nil
+}
+
+// setColumnStyles styles the data cells of text and aligned columns. Styles are
+// layered in a fixed order: the number format (text, or a number or date format
+// set per cell by setNumFmtStyle) first, then the column alignment, then the zebra fill,
+// so none of them overwrites another.
+func (e *ExcelExporter[T]) setColumnStyles(f *excelize.File, sheetName string) error {
+	// Alignment of the column -> style ID, for text and other columns
+	textStyles := make(map[excelize.Alignment]int)
+	plainStyles := make(map[excelize.Alignment]int)
+
+	for colIndex, header := range e.config.Headers {
+		alignment := e.columnAlignment(header)
+		if alignment == nil {
 			continue
 		}
 
+		styles, style := plainStyles, &excelize.Style{Alignment: alignment}
+		if e.config.TextColumns[header] {
+			// NumFmt 49 is '@' (Text)
+			styles, style.NumFmt = textStyles, 49
+		}
+		styleID, ok := styles[*alignment]
+		if !ok {
+			var err error
+			if styleID, err = f.NewStyle(style); err != nil {
+				return err
+			}
+			styles[*alignment] = styleID
+		}
+
 		colName, err := excelize.ColumnNumberToName(colIndex + 1)
 		if err != nil {
 			return err
 		}
 
-		dvRange := excelize.NewDataValidation(true)
-		dvRange.SetSqref(fmt.Sprintf("%s2:%s1000", colName, colName))
-		_ = dvRange.SetDropList(options)
-		title := "Error"
-		msg := "Invalid input"
-		dvRange.SetError(excelize.DataValidationErrorStyleWarning, title, msg)
+		startCell := fmt.Sprintf("%s2", colName)
+		endCell := fmt.Sprintf("%s%d", colName, max(10000, e.config.ValidationRows+1))
 
-		if err := f.AddDataValidation(sheetName, dvRange); err != nil {
+		if err := f.SetCellStyle(sheetName, startCell, endCell, styleID); err != nil {
 			return err
 		}
 	}
-
 	return nil
 }
 
-func (e *ExcelExporter[T]) getTextCellStyle(f *excelize.File) (int, error) {
-	// NumFmt 49 is '@' (Text)
-	return f.NewStyle(&excelize.Style{
-		NumFmt: 49,
-		Alignment: &excelize.Alignment{
-			Horizontal: "left",
-			Vertical:   "center",
-		},
-	})
-}
-
-func (e *ExcelExporter[T]) setTextColumnStyle(f *excelize.File, sheetName string) error {
-	if len(e.config.TextColumns) == 0 {
+func (e *ExcelExporter[T]) setZebraStriping(f *excelize.File, sheetName string, dataRows []int) error {
+	if !e.config.ZebraStriping || len(dataRows) == 0 || len(e.config.Headers) == 0 {
 		return nil
 	}
 
-	styleID, err := e.getTextCellStyle(f)
-	if err != nil {
-		return err
+	colors := e.config.ZebraColors
+	switch len(colors) {
+	case 0:
+		colors = []string{"", "F2F2F2"}
+	case 1:
+		colors = []string{"", colors[0]}
 	}
 
-	for colIndex, header := range e.config.Headers {
-		if e.config.TextColumns[header] {
-			colName, err := excelize.ColumnNumberToName(colIndex + 1)
+	// Existing style ID -> banded style ID, per color
+	banded := make(map[string]map[int]int)
+	for i, row := range dataRows {
+		color := colors[i%len(colors)]
+		if color == "" {
+			continue
+		}
+		if banded[color] == nil {
+			banded[color] = make(map[int]int)
+		}
+
+		for colIndex := range e.config.Headers {
+			cell, err := excelize.CoordinatesToCellName(colIndex+1, row)
+			if err != nil {
+				return err
+			}
+			baseID, err := f.GetCellStyle(sheetName, cell)
 			if err != nil {
 				return err
 			}
 
-			startCell := fmt.Sprintf("%s2", colName)
-			endCell := fmt.Sprintf("%s10000", colName)
+			styleID, ok := banded[color][baseID]
+			if !ok {
+				style, err := f.GetStyle(baseID)
+				if err != nil {
+					return err
+				}
+				style.Fill = excelize.Fill{Type: "pattern", Color: []string{color}, Pattern: 1}
+				if styleID, err = f.NewStyle(style); err != nil {
+					return err
+				}
+				banded[color][baseID] = styleID
+			}
 
-			if err := f.SetCellStyle(sheetName, startCell, endCell, styleID); err != nil {
+			if err := f.SetCellStyle(sheetName, cell, cell, styleID); err != nil {
 				return err
 			}
 		}
@@ -225,65 +835,375 @@ func (e *ExcelExporter[T]) setTextColumnStyle(f *excelize.File, sheetName string
 	return nil
 }
 
-func (e *ExcelExporter[T]) fillData(f *excelize.File, sheetName string, data []T) error {
-	if len(data) == 0 {
-		return nil
+// eachRow resolves the cells of one data item at a time and passes them to write
+// with the item's sheet row, so one pass over the data can feed several output
+// formats without holding every row in memory. It reports the pass to OnEvent.
+func (e *ExcelExporter[T]) eachRow(ctx context.Context, sheetName string, data []T, dataRows []int, write func(row int, cells []exportCell) error) error {
+	stats := e.newExportStats(sheetName)
+	e.emit(Event{Kind: EventHeaderResolved, RowIndex: 1, Columns: e.config.Headers}, stats)
+	for i, item := range data {
+		if err := ctx.Err(); err != nil {
+			e.emit(Event{Kind: EventCompleted, Err: err}, stats)
+			return err
+		}
+		cells, err := e.rowCells(item)
+		if err == nil {
+			err = write(dataRows[i], cells)
+		}
+		if err != nil {
+			err = fmt.Errorf("row %d error: %v", dataRows[i], err)
+			e.emit(Event{Kind: EventRowError, RowIndex: dataRows[i], Err: err}, stats)
+			e.emit(Event{Kind: EventCompleted, Err: err}, stats)
+			return err
+		}
+		stats.processed++
+		if stats.processed%e.config.ProgressInterval == 0 {
+			e.emit(Event{Kind: EventRowsProcessed, RowIndex: dataRows[i]}, stats)
+		}
 	}
+	e.emit(Event{Kind: EventCompleted}, stats)
+	return nil
+}
 
-	for rowIndex, item := range data {
-		if err := e.fillRow(f, sheetName, rowIndex+2, item); err != nil {
-			return fmt.Errorf("row %d error: %v", rowIndex+2, err)
+// rowLayout places data items on sheet rows around group separator rows
+type rowLayout struct {
+	dataRows  []int          // Sheet row of each data item
+	titleRows map[int]string // Sheet row -> group title
+}
+
+// layoutRows starts data on row 2 and, with GroupBy, leaves a blank row between
+// groups or puts a title row above each group
+func (e *ExcelExporter[T]) layoutRows(data []T) rowLayout {
+	layout := rowLayout{dataRows: make([]int, len(data)), titleRows: make(map[int]string)}
+	row := 2
+	var prevKey string
+	for i, item := range data {
+		if e.config.GroupBy != nil {
+			key := e.config.GroupBy(item)
+			if i == 0 || key != prevKey {
+				if e.config.GroupTitle != nil {
+					layout.titleRows[row] = e.config.GroupTitle(key)
+					row++
+				} else if i > 0 {
+					row++
+				}
+			}
+			prevKey = key
 		}
+		layout.dataRows[i] = row
+		row++
+	}
+	return layout
+}
+
+// setGroupTitles writes each title merged across all columns in bold
+func (e *ExcelExporter[T]) setGroupTitles(f *excelize.File, sheetName string, titleRows map[int]string) error {
+	if len(titleRows) == 0 || len(e.config.Headers) == 0 {
+		return nil
 	}
 
+	styleID, err := f.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}})
+	if err != nil {
+		return err
+	}
+
+	for row, title := range titleRows {
+		startCell, _ := excelize.CoordinatesToCellName(1, row)
+		endCell, _ := excelize.CoordinatesToCellName(len(e.config.Headers), row)
+		if err := f.SetCellStr(sheetName, startCell, title); err != nil {
+			return err
+		}
+		if len(e.config.Headers) > 1 {
+			if err := f.MergeCell(sheetName, startCell, endCell); err != nil {
+				return err
+			}
+		}
+		if err := f.SetCellStyle(sheetName, startCell, endCell, styleID); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
-func (e *ExcelExporter[T]) fillRow(f *excelize.File, sheetName string, row int, item T) error {
+// exportCell is one resolved cell value of a data row
+type exportCell struct {
+	value  any
+	kind   cellKind
+	format string // Number format of a number or date, "" keeps the cell style
+}
+
+type cellKind int
+
+const (
+	cellEmpty cellKind = iota // Nothing is written
+	cellValue                 // Written with the value's own cell type
+	cellText                  // Written as a string cell
+	cellDate                  // A time.Time written with the date style
+)
+
+func (e *ExcelExporter[T]) rowCells(item T) ([]exportCell, error) {
 	itemValue := reflect.ValueOf(item)
 	if itemValue.Kind() == reflect.Ptr {
 		itemValue = itemValue.Elem()
 	}
 
+	cells := make([]exportCell, len(e.config.Headers))
 	for colIndex, header := range e.config.Headers {
-		cell, err := excelize.CoordinatesToCellName(colIndex+1, row)
-		if err != nil {
-			return err
+		var value any
+		if compute, ok := e.computed[header]; ok {
+			value = e.getFieldValue(header, header, reflect.ValueOf(compute(item)))
+		} else {
+			fieldName, fieldValue, exists := e.lookupField(itemValue, header)
+			if !exists {
+				continue
+			}
+			value = e.getFieldValue(header, fieldName, fieldValue)
 		}
-
-		fieldName, exists := e.fieldMap[header]
-		if !exists {
+		if format, ok := e.config.DateColumns[header]; ok {
+			if _, isTime := value.(time.Time); isTime {
+				cells[colIndex] = exportCell{value: value, kind: cellDate, format: cmp.Or(format, defaultDateFormat)}
+				continue
+			}
+		}
+		if places, ok := e.config.FloatPrecision[header]; ok {
+			value = roundFloat(value, places)
+		}
+		value = e.boolLabel(header, value)
+		if number, ok := nonFiniteFloat(value); ok {
+			switch e.config.OnNonFinite {
+			case NonFiniteError:
+				return nil, fmt.Errorf("column %s: non-finite value %v", header, number)
+			case NonFiniteText:
+				value = strconv.FormatFloat(number, 'g', -1, 64)
+			default:
+				continue
+			}
+		}
+		if e.config.JSONColumns[header] {
+			valueStr, err := marshalJSONCell(value)
+			if err != nil {
+				return nil, fmt.Errorf("column %s: %v", header, err)
+			}
+			cells[colIndex] = exportCell{value: valueStr, kind: cellText}
+			continue
+		}
+		if e.config.TextColumns[header] {
+			cells[colIndex] = exportCell{value: fmt.Sprintf("%v", value), kind: cellText}
 			continue
 		}
 
-		fieldValue := itemValue.FieldByName(fieldName)
-		if !fieldValue.IsValid() {
+		kind := e.columnKinds[header]
+		value = coerceInferred(kind, value)
+		if _, isTime := value.(time.Time); isTime && kind == columnDate {
+			cells[colIndex] = exportCell{value: value, kind: cellDate, format: defaultDateFormat}
 			continue
 		}
+		cells[colIndex] = exportCell{value: value, kind: cellValue, format: e.numberFormat(header, value)}
+	}
 
-		value := e.getFieldValue(fieldName, fieldValue)
-		if e.config.TextColumns[header] {
-			valueStr := fmt.Sprintf("%v", value)
-			if err := f.SetCellStr(sheetName, cell, valueStr); err != nil {
+	return cells, nil
+}
+
+func (e *ExcelExporter[T]) writeRow(f *excelize.File, sheetName string, row int, cells []exportCell) error {
+	for colIndex, c := range cells {
+		if c.kind == cellEmpty {
+			continue
+		}
+		cell, err := excelize.CoordinatesToCellName(colIndex+1, row)
+		if err != nil {
+			return err
+		}
+		if c.kind == cellText {
+			if err := f.SetCellStr(sheetName, cell, c.value.(string)); err != nil {
 				return err
 			}
-		} else {
-			if err := f.SetCellValue(sheetName, cell, value); err != nil {
+			continue
+		}
+		if err := f.SetCellValue(sheetName, cell, c.value); err != nil {
+			return err
+		}
+		if c.format != "" {
+			if err := e.setNumFmtStyle(f, sheetName, cell, c.format, e.config.Headers[colIndex]); err != nil {
 				return err
 			}
 		}
 	}
-
 	return nil
 }
 
-func (e *ExcelExporter[T]) getFieldValue(fieldName string, fieldValue reflect.Value) interface{} {
+// lookupField resolves the value for a header, by struct field or by map key.
+// The returned name is the key used for CustomConverters.
+func (e *ExcelExporter[T]) lookupField(itemValue reflect.Value, header string) (string, reflect.Value, bool) {
+	if e.isMap {
+		value, ok := mapEntry(itemValue, header)
+		return header, value, ok
+	}
+
+	fieldName, exists := e.fieldMap[header]
+	if !exists {
+		fieldName, exists = e.fieldMap[normalizeHeader(header)]
+	}
+	if !exists {
+		if e.dynamicField == "" {
+			return "", reflect.Value{}, false
+		}
+		value, ok := mapEntry(itemValue.FieldByName(e.dynamicField), header)
+		return header, value, ok
+	}
+	fieldValue := itemValue.FieldByName(fieldName)
+	return fieldName, fieldValue, fieldValue.IsValid()
+}
+
+// dynamicKeys returns the sorted keys of the dynamic field across all rows that
+// are not already mapped to a regular column
+func (e *ExcelExporter[T]) dynamicKeys(data []T) []string {
+	maps := make([]reflect.Value, 0, len(data))
+	for _, item := range data {
+		itemValue := reflect.ValueOf(item)
+		if itemValue.Kind() == reflect.Ptr {
+			if itemValue.IsNil() {
+				continue
+			}
+			itemValue = itemValue.Elem()
+		}
+		maps = append(maps, itemValue.FieldByName(e.dynamicField))
+	}
+
+	var keys []string
+	for _, key := range mapKeys(maps) {
+		if _, exists := e.fieldMap[key]; !exists {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// boolLabel replaces a bool value with the column's or config's true/false text
+func (e *ExcelExporter[T]) boolLabel(header string, value any) any {
+	rv := reflect.ValueOf(value)
+	if !rv.IsValid() || rv.Kind() != reflect.Bool {
+		return value
+	}
+	labels, ok := e.config.BoolColumns[header]
+	if !ok {
+		labels = e.config.BoolValues
+	}
+	if labels == ([2]string{}) {
+		return value
+	}
+	if rv.Bool() {
+		return labels[0]
+	}
+	return labels[1]
+}
+
+// nonFiniteFloat reports whether value is a NaN or infinite float
+func nonFiniteFloat(value any) (float64, bool) {
+	rv := reflect.ValueOf(value)
+	if !rv.IsValid() || (rv.Kind() != reflect.Float64 && rv.Kind() != reflect.Float32) {
+		return 0, false
+	}
+	number := rv.Float()
+	return number, math.IsNaN(number) || math.IsInf(number, 0)
+}
+
+// marshalJSONCell renders a value as compact JSON, with nil maps and slices as empty cells
+func marshalJSONCell(value any) (string, error) {
+	if s, ok := value.(string); ok && s == "" {
+		return "", nil
+	}
+	if rv := reflect.ValueOf(value); (rv.Kind() == reflect.Map || rv.Kind() == reflect.Slice) && rv.IsNil() {
+		return "", nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// roundFloat rounds float values to the given decimal places, leaving other values untouched
+func roundFloat(value any, places int) any {
+	pow := math.Pow10(places)
+	switch v := value.(type) {
+	case float64:
+		return math.Round(v*pow) / pow
+	case float32:
+		return math.Round(float64(v)*pow) / pow
+	}
+	return value
+}
+
+// defaultDateFormat is the number format of date cells without an explicit one
+const defaultDateFormat = "yyyy-mm-dd hh:mm:ss"
+
+// numberFormat returns the number format of a numeric cell, "" for other values
+// or when the column has none
+func (e *ExcelExporter[T]) numberFormat(header string, value any) string {
+	rv := reflect.ValueOf(value)
+	if !rv.IsValid() {
+		return ""
+	}
+	kind := rv.Kind()
+	isInt := reflect.Int <= kind && kind <= reflect.Uint64
+	isFloat := kind == reflect.Float32 || kind == reflect.Float64
+	if !isInt && !isFloat {
+		return ""
+	}
+	if format, ok := e.config.NumberFormats[header]; ok || !e.config.InferNumberFormats {
+		return format
+	}
+	if isInt {
+		return "0"
+	}
+	if places, ok := e.config.FloatPrecision[header]; ok {
+		if places <= 0 {
+			return "0"
+		}
+		return "0." + strings.Repeat("0", places)
+	}
+	return e.config.FloatFormat
+}
+
+// numFmtStyleKey identifies a number format style by format and column alignment
+type numFmtStyleKey struct {
+	format string
+	header string
+}
+
+// setNumFmtStyle styles a number or date cell with format and its column's alignment
+func (e *ExcelExporter[T]) setNumFmtStyle(f *excelize.File, sheetName, cell, format, header string) error {
+	key := numFmtStyleKey{format: format, header: header}
+	if _, aligned := e.config.Alignments[header]; !aligned {
+		key.header = ""
+	}
+	styleID, ok := e.numFmtStyles[key]
+	if !ok {
+		var err error
+		styleID, err = f.NewStyle(&excelize.Style{CustomNumFmt: &format, Alignment: e.columnAlignment(key.header)})
+		if err != nil {
+			return err
+		}
+		if e.numFmtStyles == nil {
+			e.numFmtStyles = make(map[numFmtStyleKey]int)
+		}
+		e.numFmtStyles[key] = styleID
+	}
+	return f.SetCellStyle(sheetName, cell, cell, styleID)
+}
+
+// getFieldValue resolves a field to the value written for header. Pointers and
+// interfaces are followed through any number of levels, a nil one on the way
+// writes an empty cell. time.Time values are formatted as text unless header is
+// one of DateColumns; other structs implementing fmt.Stringer, on the value or
+// the pointer, are written as their String().
+func (e *ExcelExporter[T]) getFieldValue(header, fieldName string, fieldValue reflect.Value) interface{} {
 	if !fieldValue.IsValid() {
 		return ""
 	}
 
-	// Handle pointer
-	if fieldValue.Kind() == reflect.Ptr {
+	// Handle pointers and interface values, e.g. **T or map[string]any entries
+	for fieldValue.Kind() == reflect.Ptr || fieldValue.Kind() == reflect.Interface {
 		if fieldValue.IsNil() {
 			return ""
 		}
@@ -301,20 +1221,34 @@ func (e *ExcelExporter[T]) getFieldValue(fieldName string, fieldValue reflect.Va
 	case reflect.Struct:
 		if fieldValue.Type() == reflect.TypeOf(time.Time{}) {
 			if timeVal, ok := fieldValue.Interface().(time.Time); ok {
+				if e.config.Location != nil {
+					timeVal = timeVal.In(e.config.Location)
+				}
+				if _, ok := e.config.DateColumns[header]; ok {
+					return timeVal
+				}
 				return timeVal.Format("2006-01-02 15:04:05")
 			}
 		}
+		// JSON columns marshal the struct itself
+		if e.config.JSONColumns[header] {
+			break
+		}
+		if stringer, ok := fieldValue.Interface().(fmt.Stringer); ok {
+			return stringer.String()
+		}
+		if fieldValue.CanAddr() {
+			if stringer, ok := fieldValue.Addr().Interface().(fmt.Stringer); ok {
+				return stringer.String()
+			}
+		}
 	}
 
 	return fieldValue.Interface()
 }
 
-func (e *ExcelExporter[T]) setHeaderStyle(f *excelize.File, sheetName string) error {
-	if len(e.config.Headers) == 0 {
-		return nil
-	}
-
-	styleID, err := f.NewStyle(&excelize.Style{
+func defaultHeaderStyle() *excelize.Style {
+	return &excelize.Style{
 		Font: &excelize.Font{
 			Bold:  true,
 			Color: "FFFFFF",
@@ -335,7 +1269,22 @@ func (e *ExcelExporter[T]) setHeaderStyle(f *excelize.File, sheetName string) er
 			{Type: "bottom", Color: "000000", Style: 1},
 			{Type: "right", Color: "000000", Style: 1},
 		},
-	})
+	}
+}
+
+func (e *ExcelExporter[T]) setHeaderStyle(f *excelize.File, sheetName string) error {
+	if len(e.config.Headers) == 0 {
+		return nil
+	}
+
+	style := e.config.HeaderStyle
+	if style == nil {
+		style = defaultHeaderStyle()
+	} else if reflect.DeepEqual(*style, excelize.Style{}) {
+		return nil
+	}
+
+	styleID, err := f.NewStyle(style)
 	if err != nil {
 		return err
 	}
@@ -347,20 +1296,212 @@ func (e *ExcelExporter[T]) setHeaderStyle(f *excelize.File, sheetName string) er
 }
 
 func (e *ExcelExporter[T]) setColumnWidths(f *excelize.File, sheetName string) error {
-	// Default auto width logic or explicit width
+	var rows [][]string
 	for colIndex, header := range e.config.Headers {
 		colName, _ := excelize.ColumnNumberToName(colIndex + 1)
-		
-		if width, ok := e.config.ColumnWidths[header]; ok {
-			if err := f.SetColWidth(sheetName, colName, colName, width); err != nil {
-				return err
+
+		width := e.columnWidth(header)
+		if width == ColumnWidthAuto {
+			if rows == nil {
+				var err error
+				if rows, err = f.GetRows(sheetName); err != nil {
+					return err
+				}
 			}
-		} else {
-			// Default width
-			if err := f.SetColWidth(sheetName, colName, colName, 15); err != nil {
+			width = autoColumnWidth(rows, colIndex)
+		}
+
+		if err := f.SetColWidth(sheetName, colName, colName, width); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// columnWidth resolves a header's width, matching ColumnWidths keys exactly first
+// and then after normalization, falling back to DefaultColumnWidth
+func (e *ExcelExporter[T]) columnWidth(header string) float64 {
+	if width, ok := e.config.ColumnWidths[header]; ok {
+		return width
+	}
+	normalized := normalizeHeader(header)
+	for key, width := range e.config.ColumnWidths {
+		if normalizeHeader(key) == normalized {
+			return width
+		}
+	}
+	return e.config.DefaultColumnWidth
+}
+
+// normalizeHeader strips surrounding whitespace and the "*" required-column marker
+func normalizeHeader(header string) string {
+	return strings.Trim(strings.TrimSpace(header), "*")
+}
+
+// autoColumnWidth fits the widest cell of a column, counting wide (CJK) characters double
+func autoColumnWidth(rows [][]string, colIndex int) float64 {
+	maxWidth := 0
+	for _, row := range rows {
+		if colIndex >= len(row) {
+			continue
+		}
+		width := 0
+		for _, r := range row[colIndex] {
+			if r >= 0x1100 {
+				width += 2
+			} else {
+				width++
+			}
+		}
+		maxWidth = max(maxWidth, width)
+	}
+	return float64(min(max(maxWidth+2, 8), 80))
+}
+
+func (e *ExcelExporter[T]) setColumnOutlines(f *excelize.File, sheetName string) error {
+	for colIndex, header := range e.config.Headers {
+		level := e.config.OutlineLevels[header]
+		if level == 0 {
+			continue
+		}
+		colName, _ := excelize.ColumnNumberToName(colIndex + 1)
+		if err := f.SetColOutlineLevel(sheetName, colName, level); err != nil {
+			return fmt.Errorf("column %s outline: %v", header, err)
+		}
+		if e.config.CollapseOutlines {
+			if err := f.SetColVisible(sheetName, colName, false); err != nil {
 				return err
 			}
 		}
 	}
 	return nil
 }
+
+// setConditionalFormats applies ConditionalFormats from the first to the last data
+// row. GroupBy title rows inside that range hold text, which data bars and color
+// scales ignore.
+func (e *ExcelExporter[T]) setConditionalFormats(f *excelize.File, sheetName string, dataRows []int) error {
+	if len(e.config.ConditionalFormats) == 0 || len(dataRows) == 0 {
+		return nil
+	}
+	first, last := dataRows[0], dataRows[len(dataRows)-1]
+	for colIndex, header := range e.config.Headers {
+		format, ok := e.config.ConditionalFormats[header]
+		if !ok {
+			continue
+		}
+		colName, _ := excelize.ColumnNumberToName(colIndex + 1)
+		rangeRef := fmt.Sprintf("%s%d:%s%d", colName, first, colName, last)
+		if err := f.SetConditionalFormat(sheetName, rangeRef, []excelize.ConditionalFormatOptions{format}); err != nil {
+			return fmt.Errorf("column %s conditional format: %v", header, err)
+		}
+	}
+	return nil
+}
+
+// addLegendSheet writes the Legend entries, minus columns rejected by ColumnFilter,
+// to a sheet after all data sheets. The data sheets stay first and active, so
+// importers reading the first sheet are unaffected.
+func (e *ExcelExporter[T]) addLegendSheet(f *excelize.File) error {
+	if len(e.config.Legend) == 0 {
+		return nil
+	}
+	legendSheet := e.config.LegendSheetName
+	if index, _ := f.GetSheetIndex(legendSheet); index != -1 {
+		return fmt.Errorf("legend sheet name %s is already used by a data sheet", legendSheet)
+	}
+	if _, err := f.NewSheet(legendSheet); err != nil {
+		return fmt.Errorf("create legend sheet failed: %v", err)
+	}
+
+	rows := [][]string{{"Column", "Description", "Example"}}
+	for _, entry := range e.config.Legend {
+		if e.config.ColumnFilter != nil && !e.config.ColumnFilter(entry.Header) {
+			continue
+		}
+		rows = append(rows, []string{entry.Header, entry.Description, entry.Example})
+	}
+	for i, row := range rows {
+		cell, _ := excelize.CoordinatesToCellName(1, i+1)
+		if err := f.SetSheetRow(legendSheet, cell, &row); err != nil {
+			return err
+		}
+	}
+
+	headerID, err := f.NewStyle(defaultHeaderStyle())
+	if err != nil {
+		return err
+	}
+	if err := f.SetCellStyle(legendSheet, "A1", "C1", headerID); err != nil {
+		return err
+	}
+	wrapID, err := f.NewStyle(&excelize.Style{Alignment: &excelize.Alignment{Vertical: "top", WrapText: true}})
+	if err != nil {
+		return err
+	}
+	if len(rows) > 1 {
+		if err := f.SetCellStyle(legendSheet, "A2", fmt.Sprintf("C%d", len(rows)), wrapID); err != nil {
+			return err
+		}
+	}
+	for col, width := range map[string]float64{"A": 20, "B": 60, "C": 25} {
+		if err := f.SetColWidth(legendSheet, col, col, width); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setPanes freezes the header row, the first column or both
+func (e *ExcelExporter[T]) setPanes(f *excelize.File, sheetName string) error {
+	if !e.config.FreezeHeader && !e.config.FreezeFirstColumn {
+		return nil
+	}
+
+	panes := &excelize.Panes{Freeze: true}
+	col, row := 1, 1
+	if e.config.FreezeFirstColumn {
+		panes.XSplit, col = 1, 2
+	}
+	if e.config.FreezeHeader {
+		panes.YSplit, row = 1, 2
+	}
+	switch {
+	case panes.XSplit > 0 && panes.YSplit > 0:
+		panes.ActivePane = "bottomRight"
+	case panes.YSplit > 0:
+		panes.ActivePane = "bottomLeft"
+	default:
+		panes.ActivePane = "topRight"
+	}
+	panes.TopLeftCell, _ = excelize.CoordinatesToCellName(col, row)
+	panes.Selection = []excelize.Selection{{SQRef: panes.TopLeftCell, ActiveCell: panes.TopLeftCell, Pane: panes.ActivePane}}
+
+	return f.SetPanes(sheetName, panes)
+}
+
+// setSheetView applies the view options that are set, leaving the others at Excel's defaults
+func (e *ExcelExporter[T]) setSheetView(f *excelize.File, sheetName string) error {
+	if e.config.ShowGridLines == nil && e.config.ShowRowColHeaders == nil && !e.config.RightToLeft {
+		return nil
+	}
+	opts := &excelize.ViewOptions{
+		ShowGridLines:     e.config.ShowGridLines,
+		ShowRowColHeaders: e.config.ShowRowColHeaders,
+	}
+	if e.config.RightToLeft {
+		opts.RightToLeft = &e.config.RightToLeft
+	}
+	return f.SetSheetView(sheetName, 0, opts)
+}
+
+func (e *ExcelExporter[T]) setPrintTitles(f *excelize.File, sheetName string) error {
+	if !e.config.PrintTitleRows || len(e.config.Headers) == 0 {
+		return nil
+	}
+	return f.SetDefinedName(&excelize.DefinedName{
+		Name:     "_xlnm.Print_Titles",
+		RefersTo: fmt.Sprintf("'%s'!$1:$1", strings.ReplaceAll(sheetName, "'", "''")),
+		Scope:    sheetName,
+	})
+}
@@ -1,9 +1,22 @@
 package exporter
 
 import (
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/md5"
+	"errors"
+	"fmt"
 	"math"
+	"net/http/httptest"
 	"os"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/xuri/excelize/v2"
 )
 
 type TestExportData struct {
@@ -100,3 +113,1301 @@ func TestExcelExporter_UserScenario(t *testing.T) {
 	// os.WriteFile("forecast_output.xlsx", resp.Content, 0644)
 	// defer os.Remove("forecast_output.xlsx")
 }
+
+func TestExcelExporter_ZebraStriping(t *testing.T) {
+	data := []TestExportData{
+		{Name: "张三", Age: 25, Score: 88.5},
+		{Name: "李四", Age: 30, Score: 92.0},
+	}
+
+	exporter := NewExcelExporter(&ExcelExportConfig[TestExportData]{ZebraStriping: true})
+	resp, err := exporter.Export(data)
+	if err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+
+	f, err := excelize.OpenReader(bytes.NewReader(resp.Content))
+	if err != nil {
+		t.Fatalf("Open exported file failed: %v", err)
+	}
+	defer f.Close()
+
+	// Second data row is banded and keeps the text number format
+	styleID, _ := f.GetCellStyle("Sheet1", "A3")
+	style, err := f.GetStyle(styleID)
+	if err != nil {
+		t.Fatalf("GetStyle failed: %v", err)
+	}
+	if len(style.Fill.Color) != 1 || style.Fill.Color[0] != "F2F2F2" {
+		t.Errorf("Expected banded fill on A3, got %+v", style.Fill)
+	}
+	if style.NumFmt != 49 {
+		t.Errorf("Expected text format preserved on A3, got %d", style.NumFmt)
+	}
+
+	// First data row is untouched
+	styleID, _ = f.GetCellStyle("Sheet1", "B2")
+	style, _ = f.GetStyle(styleID)
+	if len(style.Fill.Color) != 0 {
+		t.Errorf("Expected no fill on B2, got %+v", style.Fill)
+	}
+}
+
+func TestExcelExporter_ConditionalFormats(t *testing.T) {
+	data := []TestExportData{
+		{Name: "张三", Age: 25, Score: 88.5},
+		{Name: "李四", Age: 30, Score: 92.0},
+		{Name: "王五", Age: 35, Score: 61.0},
+	}
+
+	exporter := NewExcelExporter(&ExcelExportConfig[TestExportData]{
+		ConditionalFormats: map[string]excelize.ConditionalFormatOptions{
+			"分数": {Type: "data_bar", Criteria: "=", MinType: "min", MaxType: "max", BarColor: "#638EC6"},
+			"年龄": {Type: "2_color_scale", Criteria: "=", MinType: "min", MaxType: "max", MinColor: "#F8696B", MaxColor: "#63BE7B"},
+		},
+	})
+	resp, err := exporter.Export(data)
+	if err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+
+	f, err := excelize.OpenReader(bytes.NewReader(resp.Content))
+	if err != nil {
+		t.Fatalf("Open exported file failed: %v", err)
+	}
+	defer f.Close()
+
+	formats, err := f.GetConditionalFormats("Sheet1")
+	if err != nil {
+		t.Fatalf("GetConditionalFormats failed: %v", err)
+	}
+	if len(formats) != 2 {
+		t.Fatalf("Expected 2 conditional format ranges, got %v", formats)
+	}
+	if bar := formats["C2:C4"]; len(bar) != 1 || bar[0].Type != "data_bar" {
+		t.Errorf("Expected data bar over C2:C4, got %+v", bar)
+	}
+	if scale := formats["B2:B4"]; len(scale) != 1 || scale[0].Type != "2_color_scale" {
+		t.Errorf("Expected color scale over B2:B4, got %+v", scale)
+	}
+}
+
+func TestExcelExporter_OnEmptyData(t *testing.T) {
+	if _, err := NewExcelExporter(&ExcelExportConfig[TestExportData]{OnEmptyData: EmptyDataError}).Export(nil); err == nil {
+		t.Error("Expected error for empty data")
+	}
+
+	resp, err := NewExcelExporter(&ExcelExportConfig[TestExportData]{OnEmptyData: EmptyDataWriteNothing}).Export(nil)
+	if err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	f, err := excelize.OpenReader(bytes.NewReader(resp.Content))
+	if err != nil {
+		t.Fatalf("Open exported file failed: %v", err)
+	}
+	defer f.Close()
+	if rows, _ := f.GetRows("Sheet1"); len(rows) != 0 {
+		t.Errorf("Expected blank sheet, got %v", rows)
+	}
+}
+
+func TestExcelExporter_HeaderStyle(t *testing.T) {
+	data := []TestExportData{{Name: "张三", Age: 25, Score: 88.5}}
+
+	resp, err := NewExcelExporter(&ExcelExportConfig[TestExportData]{HeaderStyle: &excelize.Style{}}).Export(data)
+	if err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	f, err := excelize.OpenReader(bytes.NewReader(resp.Content))
+	if err != nil {
+		t.Fatalf("Open exported file failed: %v", err)
+	}
+	defer f.Close()
+
+	if styleID, _ := f.GetCellStyle("Sheet1", "A1"); styleID != 0 {
+		t.Errorf("Expected unstyled header, got style %d", styleID)
+	}
+}
+
+func TestExcelExporter_PrintTitleRows(t *testing.T) {
+	data := []TestExportData{{Name: "张三", Age: 25, Score: 88.5}}
+
+	resp, err := NewExcelExporter(&ExcelExportConfig[TestExportData]{PrintTitleRows: true}).Export(data)
+	if err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	f, err := excelize.OpenReader(bytes.NewReader(resp.Content))
+	if err != nil {
+		t.Fatalf("Open exported file failed: %v", err)
+	}
+	defer f.Close()
+
+	names := f.GetDefinedName()
+	if len(names) != 1 || names[0].Name != "_xlnm.Print_Titles" || names[0].RefersTo != "'Sheet1'!$1:$1" {
+		t.Errorf("Unexpected defined names: %+v", names)
+	}
+}
+
+func TestExcelExporter_ExportTemplate(t *testing.T) {
+	exporter := NewExcelExporter(&ExcelExportConfig[TestExportData]{
+		Dropdowns:      map[int][]string{1: {"18", "30"}},
+		ValidationRows: 50,
+	})
+	resp, err := exporter.ExportTemplate()
+	if err != nil {
+		t.Fatalf("ExportTemplate failed: %v", err)
+	}
+	f, err := excelize.OpenReader(bytes.NewReader(resp.Content))
+	if err != nil {
+		t.Fatalf("Open exported file failed: %v", err)
+	}
+	defer f.Close()
+
+	rows, _ := f.GetRows("Sheet1")
+	if len(rows) != 1 || len(rows[0]) != 3 {
+		t.Errorf("Expected header row only, got %v", rows)
+	}
+	dvs, err := f.GetDataValidations("Sheet1")
+	if err != nil {
+		t.Fatalf("GetDataValidations failed: %v", err)
+	}
+	if len(dvs) != 1 || dvs[0].Sqref != "B2:B51" {
+		t.Errorf("Unexpected validations: %+v", dvs)
+	}
+
+	// The default keeps the original B2:B1000 range
+	resp, err = NewExcelExporter(&ExcelExportConfig[TestExportData]{
+		Dropdowns: map[int][]string{1: {"18", "30"}},
+	}).ExportTemplate()
+	if err != nil {
+		t.Fatalf("ExportTemplate failed: %v", err)
+	}
+	g, err := excelize.OpenReader(bytes.NewReader(resp.Content))
+	if err != nil {
+		t.Fatalf("Open exported file failed: %v", err)
+	}
+	defer g.Close()
+	if dvs, _ := g.GetDataValidations("Sheet1"); len(dvs) != 1 || dvs[0].Sqref != "B2:B1000" {
+		t.Errorf("Unexpected default validations: %+v", dvs)
+	}
+}
+
+func TestExcelExporter_MapTypeInference(t *testing.T) {
+	data := []map[string]any{
+		{"金额": "12.5", "日期": "2024-01-02", "邮编": "010010"},
+		{"金额": 3, "日期": "2024-01-03 08:00:00", "邮编": "200000"},
+	}
+
+	exporter := NewExcelExporter(&ExcelExportConfig[map[string]any]{
+		TextColumns: map[string]bool{"邮编": true},
+	})
+	resp, err := exporter.Export(data)
+	if err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	f, err := excelize.OpenReader(bytes.NewReader(resp.Content))
+	if err != nil {
+		t.Fatalf("Open exported file failed: %v", err)
+	}
+	defer f.Close()
+
+	// Headers are the sorted keys: 日期, 邮编, 金额
+	rows, _ := f.GetRows("Sheet1")
+	if len(rows) != 3 || rows[0][0] != "日期" || rows[0][1] != "邮编" || rows[0][2] != "金额" {
+		t.Fatalf("Unexpected rows: %v", rows)
+	}
+	if cellType, _ := f.GetCellType("Sheet1", "C2"); cellType != excelize.CellTypeUnset && cellType != excelize.CellTypeNumber {
+		t.Errorf("Expected numeric cell for 金额, got %v", cellType)
+	}
+	if rows[1][1] != "010010" {
+		t.Errorf("Expected text postal code 010010, got %s", rows[1][1])
+	}
+	if rows[1][0] != "2024-01-02 00:00:00" {
+		t.Errorf("Expected formatted date, got %s", rows[1][0])
+	}
+}
+
+func TestExcelExporter_MultipleValidationsPerColumn(t *testing.T) {
+	exporter := NewExcelExporter(&ExcelExportConfig[TestExportData]{
+		Validations: map[int][]ValidationRule{
+			0: {
+				{FromRow: 2, ToRow: 100, Options: []string{"A", "B"}},
+				{FromRow: 101, ToRow: 200, Options: []string{"C", "D"}},
+			},
+		},
+	})
+	resp, err := exporter.ExportTemplate()
+	if err != nil {
+		t.Fatalf("ExportTemplate failed: %v", err)
+	}
+	f, err := excelize.OpenReader(bytes.NewReader(resp.Content))
+	if err != nil {
+		t.Fatalf("Open exported file failed: %v", err)
+	}
+	defer f.Close()
+
+	dvs, _ := f.GetDataValidations("Sheet1")
+	if len(dvs) != 2 || dvs[0].Sqref != "A2:A100" || dvs[1].Sqref != "A101:A200" {
+		t.Errorf("Unexpected validations: %+v", dvs)
+	}
+}
+
+func TestExcelExporter_EnumValues(t *testing.T) {
+	type Account struct {
+		Name   string `excel:"姓名"`
+		Status string `excel:"状态,enum:启用|停用"`
+		Level  string `excel:"等级"`
+	}
+	resp, err := NewExcelExporter(&ExcelExportConfig[Account]{
+		EnumValues:     map[string][]string{"等级": {"A", "B"}, "姓名": {"张三"}},
+		Dropdowns:      map[int][]string{0: {"张三", "李四"}},
+		ValidationRows: 10,
+	}).ExportTemplate()
+	if err != nil {
+		t.Fatalf("ExportTemplate failed: %v", err)
+	}
+	f, err := excelize.OpenReader(bytes.NewReader(resp.Content))
+	if err != nil {
+		t.Fatalf("Open exported file failed: %v", err)
+	}
+	defer f.Close()
+
+	// The explicit dropdown of 姓名 wins over its enum values
+	dvs, _ := f.GetDataValidations("Sheet1")
+	got := make(map[string]string)
+	for _, dv := range dvs {
+		got[dv.Sqref] = dv.Formula1
+	}
+	if len(got) != 3 || got["A2:A11"] != `"张三,李四"` || got["B2:B11"] != `"启用,停用"` || got["C2:C11"] != `"A,B"` {
+		t.Errorf("Unexpected validations: %v", got)
+	}
+}
+
+func TestExcelExporter_ExportGrouped(t *testing.T) {
+	data := []TestExportData{
+		{Name: "张三", Age: 25, Score: 88.5},
+		{Name: "李四", Age: 30, Score: 92.0},
+		{Name: "王五", Age: 25, Score: 76.5},
+	}
+
+	exporter := NewExcelExporter(&ExcelExportConfig[TestExportData]{FileName: "report.xlsx"})
+	resp, err := exporter.ExportGrouped(data, func(d TestExportData) string {
+		return fmt.Sprintf("age/%d", d.Age)
+	})
+	if err != nil {
+		t.Fatalf("ExportGrouped failed: %v", err)
+	}
+	if resp.FileName != "report.zip" || resp.ContentType != "application/zip" {
+		t.Errorf("Unexpected response: %s %s", resp.FileName, resp.ContentType)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(resp.Content), int64(len(resp.Content)))
+	if err != nil {
+		t.Fatalf("Open zip failed: %v", err)
+	}
+	if len(zr.File) != 2 || zr.File[0].Name != "report_age_25.xlsx" || zr.File[1].Name != "report_age_30.xlsx" {
+		t.Fatalf("Unexpected zip entries: %v", zr.File)
+	}
+
+	rc, _ := zr.File[0].Open()
+	defer rc.Close()
+	f, err := excelize.OpenReader(rc)
+	if err != nil {
+		t.Fatalf("Open group file failed: %v", err)
+	}
+	defer f.Close()
+	if rows, _ := f.GetRows("Sheet1"); len(rows) != 3 {
+		t.Errorf("Expected header and 2 rows, got %v", rows)
+	}
+
+	// Keys that sanitize to the same name still get their own entries
+	resp, err = exporter.ExportGrouped(data, func(d TestExportData) string {
+		return map[string]string{"张三": "a/b", "李四": "a:b", "王五": "A?b"}[d.Name]
+	})
+	if err != nil {
+		t.Fatalf("ExportGrouped failed: %v", err)
+	}
+	zr, err = zip.NewReader(bytes.NewReader(resp.Content), int64(len(resp.Content)))
+	if err != nil {
+		t.Fatalf("Open zip failed: %v", err)
+	}
+	var names []string
+	for _, file := range zr.File {
+		names = append(names, file.Name)
+	}
+	if fmt.Sprint(names) != "[report_a_b.xlsx report_a_b (2).xlsx report_A_b (3).xlsx]" {
+		t.Errorf("Unexpected zip entries: %v", names)
+	}
+}
+
+func TestExcelExporter_FloatPrecision(t *testing.T) {
+	type RoundItem struct {
+		Value float64  `excel:"数值,round:2"`
+		Ptr   *float64 `excel:"指针"`
+	}
+	ptr := 3.14159
+	data := []RoundItem{{Value: 1.23456, Ptr: &ptr}}
+
+	resp, err := NewExcelExporter(&ExcelExportConfig[RoundItem]{
+		FloatPrecision: map[string]int{"指针": 3},
+	}).Export(data)
+	if err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	f, err := excelize.OpenReader(bytes.NewReader(resp.Content))
+	if err != nil {
+		t.Fatalf("Open exported file failed: %v", err)
+	}
+	defer f.Close()
+
+	if v, _ := f.GetCellValue("Sheet1", "A2", excelize.Options{RawCellValue: true}); v != "1.23" {
+		t.Errorf("Expected 1.23, got %s", v)
+	}
+	if v, _ := f.GetCellValue("Sheet1", "B2", excelize.Options{RawCellValue: true}); v != "3.142" {
+		t.Errorf("Expected 3.142, got %s", v)
+	}
+}
+
+func TestExcelExporter_ExportContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	data := []TestExportData{{Name: "张三", Age: 25, Score: 88.5}}
+	_, err := NewExcelExporter(&ExcelExportConfig[TestExportData]{}).ExportContext(ctx, data)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
+
+func TestExcelExporter_ExportGroupedSheets(t *testing.T) {
+	data := []TestExportData{
+		{Name: "张三", Age: 25, Score: 88.5},
+		{Name: "李四", Age: 30, Score: 92.0},
+		{Name: "王五", Age: 25, Score: 76.5},
+	}
+
+	exporter := NewExcelExporter(&ExcelExportConfig[TestExportData]{})
+	resp, err := exporter.ExportGroupedSheets(data, func(d TestExportData) string {
+		if d.Age == 25 {
+			return "North/East"
+		}
+		return "north_east"
+	}, "Sales - {key}")
+	if err != nil {
+		t.Fatalf("ExportGroupedSheets failed: %v", err)
+	}
+	f, err := excelize.OpenReader(bytes.NewReader(resp.Content))
+	if err != nil {
+		t.Fatalf("Open exported file failed: %v", err)
+	}
+	defer f.Close()
+
+	sheets := f.GetSheetList()
+	if len(sheets) != 2 || sheets[0] != "Sales - North_East" || sheets[1] != "Sales - north_east (2)" {
+		t.Fatalf("Unexpected sheets: %v", sheets)
+	}
+	if rows, _ := f.GetRows(sheets[0]); len(rows) != 3 {
+		t.Errorf("Expected header and 2 rows, got %v", rows)
+	}
+}
+
+func TestExcelExporter_DefaultSheetName(t *testing.T) {
+	data := []TestExportData{{Name: "张三", Age: 25}, {Name: "李四", Age: 30}}
+	sheetsOf := func(resp *DownloadResponse) []string {
+		t.Helper()
+		f, err := excelize.OpenReader(bytes.NewReader(resp.Content))
+		if err != nil {
+			t.Fatalf("Open exported file failed: %v", err)
+		}
+		defer f.Close()
+		return f.GetSheetList()
+	}
+
+	resp, err := NewExcelExporter(&ExcelExportConfig[TestExportData]{SheetName: "Sheet1"}).Export(data)
+	if err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	if sheets := sheetsOf(resp); fmt.Sprint(sheets) != "[Sheet1]" {
+		t.Errorf("Expected only Sheet1, got %v", sheets)
+	}
+
+	exporter := NewExcelExporter(&ExcelExportConfig[TestExportData]{
+		Legend:          []LegendEntry{{Header: "姓名", Description: "name"}},
+		LegendSheetName: "Sheet1",
+	})
+	resp, err = exporter.ExportGroupedSheets(data, func(d TestExportData) string { return d.Name }, "")
+	if err != nil {
+		t.Fatalf("ExportGroupedSheets failed: %v", err)
+	}
+	if sheets := sheetsOf(resp); fmt.Sprint(sheets) != "[张三 李四 Sheet1]" {
+		t.Errorf("Expected group sheets and the legend only, got %v", sheets)
+	}
+
+	resp, err = exporter.ExportGroupedSheets(data, func(TestExportData) string { return "sheet1" }, "")
+	if err != nil {
+		t.Fatalf("ExportGroupedSheets failed: %v", err)
+	}
+	if sheets := sheetsOf(resp); fmt.Sprint(sheets) != "[sheet1 (2) Sheet1]" {
+		t.Errorf("Expected the group renamed away from the legend, got %v", sheets)
+	}
+
+	f := excelize.NewFile()
+	defer f.Close()
+	if err := exporter.ExportOnto(f, "Orders", data); err != nil {
+		t.Fatalf("ExportOnto failed: %v", err)
+	}
+	if err := exporter.ExportOnto(f, "Users", data); err != nil {
+		t.Fatalf("ExportOnto failed: %v", err)
+	}
+	if sheets := f.GetSheetList(); fmt.Sprint(sheets) != "[Orders Users]" {
+		t.Errorf("Expected the blank default sheet reused, got %v", sheets)
+	}
+
+	if _, err := NewExcelExporter(&ExcelExportConfig[TestExportData]{SheetName: "a:b"}).Export(data); err == nil {
+		t.Error("Expected error for an invalid sheet name")
+	}
+}
+
+func TestExcelExporter_ColumnWidthsWithExplicitHeaders(t *testing.T) {
+	type WidthItem struct {
+		Name  string `excel:"姓名,width:30"`
+		Note  string `excel:"备注,width:auto"`
+		Other string `excel:"其他"`
+	}
+	data := []WidthItem{{Name: "张三", Note: "1234567890123456789012", Other: "x"}}
+
+	exporter := NewExcelExporter(&ExcelExportConfig[WidthItem]{
+		Headers:            []string{"姓名*", " 备注 ", "其他"},
+		DefaultColumnWidth: 12,
+	})
+	resp, err := exporter.Export(data)
+	if err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	f, err := excelize.OpenReader(bytes.NewReader(resp.Content))
+	if err != nil {
+		t.Fatalf("Open exported file failed: %v", err)
+	}
+	defer f.Close()
+
+	for col, want := range map[string]float64{"A": 30, "B": 24, "C": 12} {
+		if width, _ := f.GetColWidth("Sheet1", col); width != want {
+			t.Errorf("Expected column %s width %v, got %v", col, want, width)
+		}
+	}
+	if v, _ := f.GetCellValue("Sheet1", "A2"); v != "张三" {
+		t.Errorf("Expected 姓名 filled under normalized header, got %q", v)
+	}
+}
+
+func TestExcelExporter_BoolLabels(t *testing.T) {
+	type BoolItem struct {
+		Active  bool  `excel:"启用,bool:是|否"`
+		Visible bool  `excel:"可见"`
+		Locked  *bool `excel:"锁定"`
+	}
+	locked := true
+	data := []BoolItem{{Active: false, Visible: true, Locked: &locked}}
+
+	resp, err := NewExcelExporter(&ExcelExportConfig[BoolItem]{
+		BoolValues: [2]string{"Yes", "No"},
+	}).Export(data)
+	if err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	f, err := excelize.OpenReader(bytes.NewReader(resp.Content))
+	if err != nil {
+		t.Fatalf("Open exported file failed: %v", err)
+	}
+	defer f.Close()
+
+	rows, _ := f.GetRows("Sheet1")
+	if fmt.Sprint(rows[1]) != "[否 Yes Yes]" {
+		t.Errorf("Unexpected bool cells: %v", rows[1])
+	}
+}
+
+func TestExcelExporter_ColumnFilter(t *testing.T) {
+	isAdmin := false
+	exp := NewExcelExporter(&ExcelExportConfig[TestExportData]{
+		Dropdowns:    map[int][]string{2: {"60", "100"}},
+		ColumnFilter: func(header string) bool { return isAdmin || header != "年龄" },
+	})
+	data := []TestExportData{{Name: "张三", Age: 25, Score: 88.5}}
+
+	resp, err := exp.Export(data)
+	if err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	f, err := excelize.OpenReader(bytes.NewReader(resp.Content))
+	if err != nil {
+		t.Fatalf("Open exported file failed: %v", err)
+	}
+	defer f.Close()
+
+	rows, _ := f.GetRows("Sheet1")
+	if fmt.Sprint(rows) != "[[姓名 分数] [张三 88.5]]" {
+		t.Errorf("Unexpected filtered rows: %v", rows)
+	}
+	dvs, _ := f.GetDataValidations("Sheet1")
+	if len(dvs) != 1 || !strings.HasPrefix(dvs[0].Sqref, "B") {
+		t.Errorf("Expected dropdown moved to column B, got %+v", dvs)
+	}
+
+	isAdmin = true
+	resp, err = exp.Export(data)
+	if err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	f2, _ := excelize.OpenReader(bytes.NewReader(resp.Content))
+	defer f2.Close()
+	if header, _ := f2.GetRows("Sheet1"); len(header[0]) != 3 {
+		t.Errorf("Expected all columns for admin, got %v", header[0])
+	}
+}
+
+func TestExcelExporter_NonFiniteFloats(t *testing.T) {
+	type RatioItem struct {
+		Name  string  `excel:"名称"`
+		Ratio float64 `excel:"比率"`
+	}
+	data := []RatioItem{{"a", math.NaN()}, {"b", math.Inf(1)}, {"c", 0.5}}
+
+	resp, err := NewExcelExporter(&ExcelExportConfig[RatioItem]{}).Export(data)
+	if err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	f, err := excelize.OpenReader(bytes.NewReader(resp.Content))
+	if err != nil {
+		t.Fatalf("Open exported file failed: %v", err)
+	}
+	defer f.Close()
+	rows, _ := f.GetRows("Sheet1")
+	if fmt.Sprint(rows[1:]) != "[[a] [b] [c 0.5]]" {
+		t.Errorf("Expected blank non-finite cells, got %v", rows[1:])
+	}
+
+	resp, err = NewExcelExporter(&ExcelExportConfig[RatioItem]{OnNonFinite: NonFiniteText}).Export(data)
+	if err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	f2, _ := excelize.OpenReader(bytes.NewReader(resp.Content))
+	defer f2.Close()
+	if v, _ := f2.GetCellValue("Sheet1", "B3"); v != "+Inf" {
+		t.Errorf("Expected +Inf text, got %q", v)
+	}
+
+	if _, err := NewExcelExporter(&ExcelExportConfig[RatioItem]{OnNonFinite: NonFiniteError}).Export(data); err == nil {
+		t.Error("Expected error for NaN value")
+	}
+}
+
+func TestExcelExporter_DocProps(t *testing.T) {
+	resp, err := NewExcelExporter(&ExcelExportConfig[TestExportData]{
+		DocProps: &excelize.DocProperties{Creator: "报表系统", Title: "月度报表", Keywords: "sales"},
+		AppProps: &excelize.AppProperties{Company: "Acme"},
+	}).Export([]TestExportData{{Name: "张三", Age: 25, Score: 88.5}})
+	if err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	f, err := excelize.OpenReader(bytes.NewReader(resp.Content))
+	if err != nil {
+		t.Fatalf("Open exported file failed: %v", err)
+	}
+	defer f.Close()
+
+	doc, _ := f.GetDocProps()
+	if doc.Creator != "报表系统" || doc.Title != "月度报表" || doc.Keywords != "sales" {
+		t.Errorf("Unexpected doc props: %+v", doc)
+	}
+	if app, _ := f.GetAppProps(); app.Company != "Acme" {
+		t.Errorf("Unexpected app props: %+v", app)
+	}
+}
+
+func TestDownloadResponse_WriteHTTP(t *testing.T) {
+	resp := &DownloadResponse{
+		FileName:    "销售报表 2024.xlsx",
+		ContentType: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+		Content:     []byte("data"),
+	}
+
+	rec := httptest.NewRecorder()
+	if err := resp.WriteHTTP(rec); err != nil {
+		t.Fatalf("WriteHTTP failed: %v", err)
+	}
+
+	want := `attachment; filename="____ 2024.xlsx"; filename*=UTF-8''%E9%94%80%E5%94%AE%E6%8A%A5%E8%A1%A8%202024.xlsx`
+	if got := rec.Header().Get("Content-Disposition"); got != want {
+		t.Errorf("Unexpected Content-Disposition: %s", got)
+	}
+	if rec.Header().Get("Content-Length") != "4" || rec.Body.String() != "data" {
+		t.Errorf("Unexpected response: %v %q", rec.Header(), rec.Body.String())
+	}
+}
+
+func TestDownloadResponse_ContentDispositionHeader(t *testing.T) {
+	cases := map[string]string{
+		"report.xlsx":  `attachment; filename="report.xlsx"; filename*=UTF-8''report.xlsx`,
+		"报表.xlsx":      `attachment; filename="__.xlsx"; filename*=UTF-8''%E6%8A%A5%E8%A1%A8.xlsx`,
+		`a"b;c=d.xlsx`: `attachment; filename="a_b;c=d.xlsx"; filename*=UTF-8''a%22b%3Bc%3Dd.xlsx`,
+	}
+	for name, want := range cases {
+		if got := (&DownloadResponse{FileName: name}).ContentDispositionHeader(); got != want {
+			t.Errorf("%s: expected %s, got %s", name, want, got)
+		}
+	}
+}
+
+func TestDownloadResponse_DataURI(t *testing.T) {
+	resp := &DownloadResponse{ContentType: "text/csv", Content: []byte("a,b")}
+
+	uri, err := resp.DataURI(0)
+	if err != nil {
+		t.Fatalf("DataURI failed: %v", err)
+	}
+	if uri != "data:text/csv;base64,YSxi" {
+		t.Errorf("Unexpected data URI: %s", uri)
+	}
+	if _, err := resp.DataURI(2); err == nil {
+		t.Error("Expected error for content above the limit")
+	}
+}
+
+func TestExcelExporter_ExportGzip(t *testing.T) {
+	resp, err := NewExcelExporter(&ExcelExportConfig[TestExportData]{FileName: "报表.xlsx"}).ExportGzip([]TestExportData{{Name: "张三"}})
+	if err != nil {
+		t.Fatalf("ExportGzip failed: %v", err)
+	}
+	if resp.FileName != "报表.xlsx.gz" || resp.ContentType != "application/gzip" || resp.ContentEncoding != "gzip" || resp.FileSize != int64(len(resp.Content)) {
+		t.Errorf("Unexpected gzip response: %+v", resp)
+	}
+
+	zr, err := gzip.NewReader(bytes.NewReader(resp.Content))
+	if err != nil {
+		t.Fatalf("gzip.NewReader failed: %v", err)
+	}
+	f, err := excelize.OpenReader(zr)
+	if err != nil {
+		t.Fatalf("Open decompressed file failed: %v", err)
+	}
+	defer f.Close()
+	if name, _ := f.GetCellValue("Sheet1", "A2"); name != "张三" {
+		t.Errorf("Expected round-tripped cell, got %q", name)
+	}
+}
+
+func TestDownloadResponse_Checksum(t *testing.T) {
+	resp := &DownloadResponse{Content: []byte("abc")}
+
+	if got := resp.SHA256(); got != "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad" {
+		t.Errorf("Unexpected SHA-256: %s", got)
+	}
+	if got := resp.Checksum(md5.New); got != "900150983cd24fb0d6963f7d28e17f72" {
+		t.Errorf("Unexpected MD5: %s", got)
+	}
+}
+
+func TestExcelExporter_GroupSeparators(t *testing.T) {
+	data := []TestExportData{
+		{Name: "张三", Age: 25, Score: 1},
+		{Name: "李四", Age: 25, Score: 2},
+		{Name: "王五", Age: 30, Score: 3},
+	}
+	byAge := func(d TestExportData) string { return strconv.Itoa(d.Age) }
+
+	resp, err := NewExcelExporter(&ExcelExportConfig[TestExportData]{GroupBy: byAge}).Export(data)
+	if err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	f, _ := excelize.OpenReader(bytes.NewReader(resp.Content))
+	rows, _ := f.GetRows("Sheet1")
+	f.Close()
+	if len(rows) != 5 || len(rows[3]) != 0 || rows[4][0] != "王五" {
+		t.Errorf("Expected a blank row between groups, got %v", rows)
+	}
+
+	resp, err = NewExcelExporter(&ExcelExportConfig[TestExportData]{
+		GroupBy:       byAge,
+		GroupTitle:    func(key string) string { return key + "岁" },
+		ZebraStriping: true,
+	}).Export(data)
+	if err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	f, _ = excelize.OpenReader(bytes.NewReader(resp.Content))
+	defer f.Close()
+	rows, _ = f.GetRows("Sheet1")
+	if len(rows) != 6 || rows[1][0] != "25岁" || rows[4][0] != "30岁" || rows[5][0] != "王五" {
+		t.Errorf("Expected title rows above groups, got %v", rows)
+	}
+	if merged, _ := f.GetMergeCells("Sheet1"); len(merged) != 2 {
+		t.Errorf("Expected merged title rows, got %d", len(merged))
+	}
+
+	// The second data row is banded even though a title row sits between them
+	styleID, _ := f.GetCellStyle("Sheet1", "B4")
+	style, _ := f.GetStyle(styleID)
+	if len(style.Fill.Color) == 0 || style.Fill.Color[0] != "F2F2F2" {
+		t.Errorf("Expected banded fill on second data row, got %+v", style.Fill)
+	}
+}
+
+func TestNewExcelExporterE(t *testing.T) {
+	if _, err := NewExcelExporterE(&ExcelExportConfig[TestExportData]{}); err != nil {
+		t.Errorf("Expected valid config, got %v", err)
+	}
+	if _, err := NewExcelExporterE(&ExcelExportConfig[map[string]any]{}); err != nil {
+		t.Errorf("Expected map rows to be valid, got %v", err)
+	}
+
+	_, err := NewExcelExporterE(&ExcelExportConfig[TestExportData]{
+		Headers:          []string{"姓名", "年纪"},
+		Dropdowns:        map[int][]string{5: {"a"}},
+		CustomConverters: map[string]func(any) any{"Nam": nil},
+	})
+	for _, want := range []string{"header 年纪", "Dropdowns column 5", "unknown field or header Nam"} {
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected error containing %q, got %v", want, err)
+		}
+	}
+
+	if _, err := NewExcelExporterE(&ExcelExportConfig[int]{}); err == nil {
+		t.Error("Expected non-struct error")
+	}
+}
+
+func TestExcelExporter_ComputedColumns(t *testing.T) {
+	type Person struct {
+		FirstName string  `excel:"名"`
+		LastName  string  `excel:"姓"`
+		Height    float64 `excel:"身高"`
+	}
+	data := []Person{{FirstName: "San", LastName: "Zhang", Height: 1.756}}
+
+	resp, err := NewExcelExporter(&ExcelExportConfig[Person]{
+		ComputedColumns: []ComputedColumn[Person]{
+			{Header: "全名", Value: func(p Person) any { return p.FirstName + " " + p.LastName }},
+			{Header: "身高(cm)", Value: func(p Person) any { return p.Height * 100 }},
+		},
+		FloatPrecision: map[string]int{"身高(cm)": 0},
+		ColumnWidths:   map[string]float64{"全名": 30},
+	}).Export(data)
+	if err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	f, err := excelize.OpenReader(bytes.NewReader(resp.Content))
+	if err != nil {
+		t.Fatalf("Open exported file failed: %v", err)
+	}
+	defer f.Close()
+
+	rows, _ := f.GetRows("Sheet1")
+	if fmt.Sprint(rows) != "[[名 姓 身高 全名 身高(cm)] [San Zhang 1.756 San Zhang 176]]" {
+		t.Errorf("Unexpected rows: %v", rows)
+	}
+	if width, _ := f.GetColWidth("Sheet1", "D"); width != 30 {
+		t.Errorf("Expected computed column width 30, got %v", width)
+	}
+}
+
+func TestExcelExporter_OutlineLevels(t *testing.T) {
+	data := []TestExportData{{Name: "张三", Age: 25, Score: 88.5}}
+
+	resp, err := NewExcelExporter(&ExcelExportConfig[TestExportData]{
+		OutlineLevels:    map[string]uint8{"年龄": 1, "分数": 1},
+		CollapseOutlines: true,
+	}).Export(data)
+	if err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	f, err := excelize.OpenReader(bytes.NewReader(resp.Content))
+	if err != nil {
+		t.Fatalf("Open exported file failed: %v", err)
+	}
+	defer f.Close()
+
+	for col, want := range map[string]uint8{"A": 0, "B": 1, "C": 1} {
+		level, _ := f.GetColOutlineLevel("Sheet1", col)
+		visible, _ := f.GetColVisible("Sheet1", col)
+		if level != want || visible != (want == 0) {
+			t.Errorf("Column %s: level %d visible %v", col, level, visible)
+		}
+	}
+	if width, _ := f.GetColWidth("Sheet1", "C"); width != 20 {
+		t.Errorf("Expected outline to keep column width, got %v", width)
+	}
+
+	_, err = NewExcelExporter(&ExcelExportConfig[TestExportData]{
+		OutlineLevels: map[string]uint8{"年龄": 8},
+	}).Export(data)
+	if err == nil {
+		t.Error("Expected error for outline level above 7")
+	}
+}
+
+func TestExcelExporter_ExportFormats(t *testing.T) {
+	data := []TestExportData{{Name: "张三", Age: 25, Score: 88.5}, {Name: "O'Neil, Jr.", Age: 30, Score: 1e-7}}
+
+	files, err := NewExcelExporter(&ExcelExportConfig[TestExportData]{FileName: "users.xlsx"}).
+		ExportFormats(data, FormatXLSX, FormatCSV)
+	if err != nil {
+		t.Fatalf("ExportFormats failed: %v", err)
+	}
+
+	csvFile := files[FormatCSV]
+	if csvFile.FileName != "users.csv" || !strings.HasPrefix(csvFile.ContentType, "text/csv") {
+		t.Errorf("Unexpected CSV response: %s %s", csvFile.FileName, csvFile.ContentType)
+	}
+	want := "\ufeff姓名,年龄,分数\n张三,25,88.5\n\"O'Neil, Jr.\",30,0.0000001\n"
+	if string(csvFile.Content) != want {
+		t.Errorf("Unexpected CSV content: %q", csvFile.Content)
+	}
+
+	f, err := excelize.OpenReader(bytes.NewReader(files[FormatXLSX].Content))
+	if err != nil {
+		t.Fatalf("Open exported file failed: %v", err)
+	}
+	defer f.Close()
+	rows, _ := f.GetRows("Sheet1")
+	if files[FormatXLSX].FileName != "users.xlsx" || len(rows) != 3 || rows[1][0] != "张三" {
+		t.Errorf("Unexpected workbook rows: %v", rows)
+	}
+}
+
+func TestExcelExporter_FreezePanes(t *testing.T) {
+	data := []TestExportData{{Name: "张三", Age: 25, Score: 88.5}}
+
+	cases := []struct {
+		header, column bool
+		x, y           int
+		topLeft        string
+	}{
+		{header: true, y: 1, topLeft: "A2"},
+		{column: true, x: 1, topLeft: "B1"},
+		{header: true, column: true, x: 1, y: 1, topLeft: "B2"},
+	}
+	for _, tc := range cases {
+		resp, err := NewExcelExporter(&ExcelExportConfig[TestExportData]{
+			FreezeHeader:      tc.header,
+			FreezeFirstColumn: tc.column,
+		}).Export(data)
+		if err != nil {
+			t.Fatalf("Export failed: %v", err)
+		}
+		f, err := excelize.OpenReader(bytes.NewReader(resp.Content))
+		if err != nil {
+			t.Fatalf("Open exported file failed: %v", err)
+		}
+		panes, err := f.GetPanes("Sheet1")
+		f.Close()
+		if err != nil {
+			t.Fatalf("GetPanes failed: %v", err)
+		}
+		if !panes.Freeze || panes.XSplit != tc.x || panes.YSplit != tc.y || panes.TopLeftCell != tc.topLeft {
+			t.Errorf("header=%v column=%v: unexpected panes %+v", tc.header, tc.column, panes)
+		}
+	}
+}
+
+func TestExcelExporter_SheetView(t *testing.T) {
+	hidden := false
+	resp, err := NewExcelExporter(&ExcelExportConfig[TestExportData]{
+		ShowGridLines:     &hidden,
+		ShowRowColHeaders: &hidden,
+		FreezeHeader:      true,
+	}).Export([]TestExportData{{Name: "张三"}})
+	if err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	f, err := excelize.OpenReader(bytes.NewReader(resp.Content))
+	if err != nil {
+		t.Fatalf("Open exported file failed: %v", err)
+	}
+	defer f.Close()
+
+	view, err := f.GetSheetView("Sheet1", 0)
+	if err != nil {
+		t.Fatalf("GetSheetView failed: %v", err)
+	}
+	if view.ShowGridLines == nil || *view.ShowGridLines || view.ShowRowColHeaders == nil || *view.ShowRowColHeaders {
+		t.Errorf("Expected gridlines and headers hidden, got %+v", view)
+	}
+	if panes, _ := f.GetPanes("Sheet1"); !panes.Freeze {
+		t.Error("Expected the frozen header to survive the view options")
+	}
+}
+
+func TestExcelExporter_RightToLeft(t *testing.T) {
+	resp, err := NewExcelExporter(&ExcelExportConfig[TestExportData]{
+		RightToLeft:       true,
+		FreezeHeader:      true,
+		FreezeFirstColumn: true,
+		Alignments:        map[string]excelize.Alignment{"分数": {Horizontal: "center"}},
+	}).Export([]TestExportData{{Name: "张三", Score: 1}})
+	if err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	f, err := excelize.OpenReader(bytes.NewReader(resp.Content))
+	if err != nil {
+		t.Fatalf("Open exported file failed: %v", err)
+	}
+	defer f.Close()
+
+	if view, _ := f.GetSheetView("Sheet1", 0); view.RightToLeft == nil || !*view.RightToLeft {
+		t.Errorf("Expected a right-to-left sheet, got %+v", view)
+	}
+	if panes, _ := f.GetPanes("Sheet1"); !panes.Freeze || panes.XSplit != 1 || panes.YSplit != 1 {
+		t.Errorf("Expected frozen panes to survive the view options, got %+v", panes)
+	}
+	for cell, want := range map[string]string{"A2": "right", "C2": "center"} {
+		styleID, _ := f.GetCellStyle("Sheet1", cell)
+		if style, _ := f.GetStyle(styleID); style.Alignment == nil || style.Alignment.Horizontal != want {
+			t.Errorf("Expected %s aligned %s, got %+v", cell, want, style.Alignment)
+		}
+	}
+
+	resp, err = NewExcelExporter(&ExcelExportConfig[TestExportData]{}).Export([]TestExportData{{Name: "张三"}})
+	if err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	if f, err = excelize.OpenReader(bytes.NewReader(resp.Content)); err != nil {
+		t.Fatalf("Open exported file failed: %v", err)
+	}
+	defer f.Close()
+	if view, _ := f.GetSheetView("Sheet1", 0); view.RightToLeft != nil && *view.RightToLeft {
+		t.Error("Expected sheets to default to left-to-right")
+	}
+}
+
+func TestExcelExporter_Legend(t *testing.T) {
+	resp, err := NewExcelExporter(&ExcelExportConfig[TestExportData]{
+		Legend: []LegendEntry{
+			{Header: "姓名", Description: "Full legal name", Example: "张三"},
+			{Header: "年龄", Description: "Age in years", Example: "25"},
+		},
+		ColumnFilter: func(header string) bool { return header != "年龄" },
+	}).ExportTemplate()
+	if err != nil {
+		t.Fatalf("ExportTemplate failed: %v", err)
+	}
+	f, err := excelize.OpenReader(bytes.NewReader(resp.Content))
+	if err != nil {
+		t.Fatalf("Open exported file failed: %v", err)
+	}
+	defer f.Close()
+
+	if sheets := f.GetSheetList(); fmt.Sprint(sheets) != "[Sheet1 Instructions]" || f.GetActiveSheetIndex() != 0 {
+		t.Errorf("Expected data sheet first and active, got %v active %d", sheets, f.GetActiveSheetIndex())
+	}
+	rows, _ := f.GetRows("Instructions")
+	if fmt.Sprint(rows) != "[[Column Description Example] [姓名 Full legal name 张三]]" {
+		t.Errorf("Unexpected legend rows: %v", rows)
+	}
+
+	_, err = NewExcelExporter(&ExcelExportConfig[TestExportData]{
+		Legend:          []LegendEntry{{Header: "姓名"}},
+		LegendSheetName: "Sheet1",
+	}).Export(nil)
+	if err == nil {
+		t.Error("Expected error for legend sheet named like the data sheet")
+	}
+
+	legend := []LegendEntry{{Header: "姓名", Description: "Full legal name"}}
+	resp, err = NewExcelExporter(&ExcelExportConfig[TestExportData]{Legend: legend}).ExportGroupedSheets(
+		[]TestExportData{{Name: "a"}, {Name: "b"}},
+		func(d TestExportData) string { return map[string]string{"a": "Instructions", "b": "Other"}[d.Name] }, "")
+	if err != nil {
+		t.Fatalf("ExportGroupedSheets failed: %v", err)
+	}
+	g, _ := excelize.OpenReader(bytes.NewReader(resp.Content))
+	defer g.Close()
+	if sheets := g.GetSheetList(); fmt.Sprint(sheets) != "[Instructions (2) Other Instructions]" {
+		t.Errorf("Expected legend sheet after all group sheets, got %v", sheets)
+	}
+}
+
+type productCode struct {
+	Prefix string
+	N      int
+}
+
+func (c *productCode) String() string { return fmt.Sprintf("%s-%03d", c.Prefix, c.N) }
+
+func TestExcelExporter_PointerFields(t *testing.T) {
+	type PointerItem struct {
+		Due     *time.Time   `excel:"Due"`
+		DueDate *time.Time   `excel:"DueDate,date:yyyy-mm-dd"`
+		Count   **int        `excel:"Count"`
+		Code    *productCode `excel:"Code"`
+		Note    *any         `excel:"Note"`
+	}
+	due := time.Date(2024, 3, 5, 8, 30, 0, 0, time.UTC)
+	count := 7
+	countPtr := &count
+	var note any = "ok"
+	data := []PointerItem{
+		{Due: &due, DueDate: &due, Count: &countPtr, Code: &productCode{Prefix: "PX", N: 7}, Note: &note},
+		{Count: new(*int)},
+	}
+
+	resp, err := NewExcelExporter(&ExcelExportConfig[PointerItem]{}).Export(data)
+	if err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	f, err := excelize.OpenReader(bytes.NewReader(resp.Content))
+	if err != nil {
+		t.Fatalf("Open exported file failed: %v", err)
+	}
+	defer f.Close()
+
+	// The all-nil row is blank, so GetRows leaves it out
+	rows, _ := f.GetRows("Sheet1")
+	if len(rows) != 2 {
+		t.Fatalf("Expected header, one row and a blank row, got %v", rows)
+	}
+	if got := fmt.Sprint(rows[1]); got != "[2024-03-05 08:30:00 2024-03-05 7 PX-007 ok]" {
+		t.Errorf("Unexpected pointer row: %s", got)
+	}
+	if cellType, _ := f.GetCellType("Sheet1", "B2"); cellType == excelize.CellTypeSharedString || cellType == excelize.CellTypeInlineString {
+		t.Error("Expected *time.Time in a date column to be written as a date")
+	}
+	if cellType, _ := f.GetCellType("Sheet1", "C2"); cellType != excelize.CellTypeUnset && cellType != excelize.CellTypeNumber {
+		t.Errorf("Expected **int to be written as a number, got %v", cellType)
+	}
+}
+
+func TestExcelExporter_DateColumns(t *testing.T) {
+	type Event struct {
+		Name string    `excel:"名称"`
+		Day  time.Time `excel:"日期,date:yyyy-mm-dd"`
+		At   time.Time `excel:"时间"`
+	}
+	day := time.Date(2024, 3, 5, 23, 30, 0, 0, time.UTC)
+	resp, err := NewExcelExporter(&ExcelExportConfig[Event]{
+		DateColumns: map[string]string{"时间": ""},
+		Location:    time.FixedZone("UTC+8", 8*3600),
+	}).Export([]Event{{Name: "a", Day: day, At: day}})
+	if err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	f, err := excelize.OpenReader(bytes.NewReader(resp.Content))
+	if err != nil {
+		t.Fatalf("Open exported file failed: %v", err)
+	}
+	defer f.Close()
+
+	raw, _ := f.GetCellValue("Sheet1", "B2", excelize.Options{RawCellValue: true})
+	if serial, err := strconv.ParseFloat(raw, 64); err != nil || serial < 45357 || serial >= 45358 {
+		t.Errorf("Expected a date serial on 2024-03-06 in the configured zone, got %q", raw)
+	}
+	rows, _ := f.GetRows("Sheet1")
+	if rows[1][1] != "2024-03-06" || rows[1][2] != "2024-03-06 07:30:00" {
+		t.Errorf("Expected cells displayed in their number formats, got %v", rows[1])
+	}
+}
+
+func TestExcelExporter_Alignments(t *testing.T) {
+	type Order struct {
+		Code   string    `excel:"编号,text"`
+		Amount float64   `excel:"金额,align:right"`
+		Day    time.Time `excel:"日期,date:yyyy-mm-dd,align:center|top"`
+	}
+	resp, err := NewExcelExporter(&ExcelExportConfig[Order]{
+		Alignments:    map[string]excelize.Alignment{"编号": {Horizontal: "center"}},
+		ZebraStriping: true,
+		ZebraColors:   []string{"F2F2F2", ""},
+	}).Export([]Order{{Code: "007", Amount: 1.5, Day: time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC)}})
+	if err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	f, err := excelize.OpenReader(bytes.NewReader(resp.Content))
+	if err != nil {
+		t.Fatalf("Open exported file failed: %v", err)
+	}
+	defer f.Close()
+
+	style := func(cell string) *excelize.Style {
+		styleID, _ := f.GetCellStyle("Sheet1", cell)
+		s, err := f.GetStyle(styleID)
+		if err != nil || s.Alignment == nil {
+			t.Fatalf("Expected an aligned style on %s, got %+v (%v)", cell, s, err)
+		}
+		return s
+	}
+	if s := style("A2"); s.Alignment.Horizontal != "center" || s.NumFmt != 49 || s.Fill.Pattern != 1 {
+		t.Errorf("Expected centered zebra text cell, got %+v", s)
+	}
+	if s := style("B2"); s.Alignment.Horizontal != "right" || s.Fill.Pattern != 1 {
+		t.Errorf("Expected right aligned zebra cell, got %+v", s)
+	}
+	if s := style("C2"); s.Alignment.Horizontal != "center" || s.Alignment.Vertical != "top" || s.CustomNumFmt == nil || *s.CustomNumFmt != "yyyy-mm-dd" {
+		t.Errorf("Expected aligned date cell keeping its number format, got %+v", s)
+	}
+	if s := style("B3"); s.Alignment.Horizontal != "right" {
+		t.Errorf("Expected alignment on rows below the data, got %+v", s)
+	}
+}
+
+func TestExcelExporter_NumberFormats(t *testing.T) {
+	type Line struct {
+		Count  int     `excel:"数量"`
+		Price  float64 `excel:"单价"`
+		Rate   float64 `excel:"比率,round:3"`
+		Amount float64 `excel:"金额,numfmt:#0.0"`
+		Note   string  `excel:"备注"`
+	}
+	resp, err := NewExcelExporter(&ExcelExportConfig[Line]{
+		InferNumberFormats: true,
+		Alignments:         map[string]excelize.Alignment{"单价": {Horizontal: "right"}},
+	}).Export([]Line{{Count: 3, Price: 12.5, Rate: 0.12345, Amount: 7, Note: "x"}})
+	if err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	f, err := excelize.OpenReader(bytes.NewReader(resp.Content))
+	if err != nil {
+		t.Fatalf("Open exported file failed: %v", err)
+	}
+	defer f.Close()
+
+	rows, _ := f.GetRows("Sheet1")
+	if fmt.Sprint(rows[1]) != "[3 12.50 0.123 7.0 x]" {
+		t.Errorf("Unexpected formatted row: %v", rows[1])
+	}
+	styleID, _ := f.GetCellStyle("Sheet1", "B2")
+	if style, _ := f.GetStyle(styleID); style.Alignment == nil || style.Alignment.Horizontal != "right" {
+		t.Errorf("Expected number format to keep the column alignment, got %+v", style)
+	}
+	if cellType, _ := f.GetCellType("Sheet1", "B2"); cellType != excelize.CellTypeUnset && cellType != excelize.CellTypeNumber {
+		t.Errorf("Expected a numeric cell, got %v", cellType)
+	}
+}
+
+func TestExcelExporter_ExportOnto(t *testing.T) {
+	f := excelize.NewFile()
+	defer f.Close()
+	_ = f.SetCellValue("Sheet1", "A1", "cover")
+
+	exporter := NewExcelExporter(&ExcelExportConfig[TestExportData]{FreezeHeader: true})
+	if err := exporter.ExportOnto(f, "Data", []TestExportData{{Name: "张三", Age: 25}}); err != nil {
+		t.Fatalf("ExportOnto failed: %v", err)
+	}
+	if err := exporter.ExportOnto(f, "", []TestExportData{{Name: "李四"}}); err != nil {
+		t.Fatalf("ExportOnto failed: %v", err)
+	}
+
+	if sheets := f.GetSheetList(); fmt.Sprint(sheets) != "[Sheet1 Data]" {
+		t.Errorf("Expected the data sheet added next to the existing one, got %v", sheets)
+	}
+	if rows, _ := f.GetRows("Sheet1"); fmt.Sprint(rows) != "[[姓名 年龄 分数] [李四 0 0]]" {
+		t.Errorf("Expected the default sheet to be written over, got %v", rows)
+	}
+	rows, _ := f.GetRows("Data")
+	if len(rows) != 2 || rows[1][0] != "张三" {
+		t.Errorf("Unexpected data sheet rows: %v", rows)
+	}
+	if panes, _ := f.GetPanes("Data"); !panes.Freeze {
+		t.Error("Expected sheet options applied to the supplied workbook")
+	}
+}
+
+func TestExcelExporter_ExtraColumns(t *testing.T) {
+	type Forecast struct {
+		Account  string            `excel:"用户编号"`
+		TimeData map[string]string `excel:"extra"`
+	}
+	data := []Forecast{
+		{Account: "C1", TimeData: map[string]string{"01:00": "200", "00:30": "100"}},
+		{Account: "C2", TimeData: map[string]string{"01:30": "300"}},
+	}
+
+	headers := func(config *ExcelExportConfig[Forecast]) [][]string {
+		t.Helper()
+		resp, err := NewExcelExporter(config).Export(data)
+		if err != nil {
+			t.Fatalf("Export failed: %v", err)
+		}
+		f, err := excelize.OpenReader(bytes.NewReader(resp.Content))
+		if err != nil {
+			t.Fatalf("Open exported file failed: %v", err)
+		}
+		defer f.Close()
+		rows, _ := f.GetRows("Sheet1")
+		return rows
+	}
+
+	if rows := headers(&ExcelExportConfig[Forecast]{}); strings.Contains(strings.Join(rows[0], " "), "00:30") {
+		t.Errorf("Expected map keys left out by default, got headers %v", rows[0])
+	}
+	rows := headers(&ExcelExportConfig[Forecast]{ExtraColumns: true})
+	if fmt.Sprint(rows) != "[[用户编号 00:30 01:00 01:30] [C1 100 200] [C2   300]]" {
+		t.Errorf("Unexpected rows: %v", rows)
+	}
+}
+
+func TestExcelExporter_ExportFormatsDateColumns(t *testing.T) {
+	type Visit struct {
+		Name string    `excel:"姓名"`
+		Day  time.Time `excel:"日期,date:yyyy/mm/dd"`
+		At   time.Time `excel:"时间,date:h:mm AM/PM"`
+		Seen time.Time `excel:"记录"`
+	}
+	at := time.Date(2024, 3, 5, 14, 7, 9, 0, time.UTC)
+	files, err := NewExcelExporter(&ExcelExportConfig[Visit]{}).
+		ExportFormats([]Visit{{Name: "张三", Day: at, At: at, Seen: at}}, FormatCSV)
+	if err != nil {
+		t.Fatalf("ExportFormats failed: %v", err)
+	}
+	want := "\ufeff姓名,日期,时间,记录\n张三,2024/03/05,2:07 PM,2024-03-05 14:07:09\n"
+	if string(files[FormatCSV].Content) != want {
+		t.Errorf("Unexpected CSV content: %q", files[FormatCSV].Content)
+	}
+}
+
+func TestDateLayout(t *testing.T) {
+	cases := map[string]string{
+		"yyyy-mm-dd hh:mm:ss":  "2006-01-02 15:04:05",
+		"yyyy年m月d日":            "2006年1月2日",
+		"mmm d, yy":            "Jan 2, 06",
+		"[$-409]dddd, mmmm dd": "Monday, January 02",
+		"mm:ss":                "04:05",
+		`hh"h"mm`:              "15h04",
+	}
+	for format, want := range cases {
+		if got := dateLayout(format); got != want {
+			t.Errorf("dateLayout(%q) = %q, want %q", format, got, want)
+		}
+	}
+}
+
+func TestExcelExporter_OnEvent(t *testing.T) {
+	data := []TestExportData{{Name: "张三", Age: 25}, {Name: "李四", Age: 30}, {Name: "王五", Score: math.NaN()}}
+
+	var events []Event
+	_, err := NewExcelExporter(&ExcelExportConfig[TestExportData]{
+		ProgressInterval: 2,
+		OnNonFinite:      NonFiniteError,
+		OnEvent:          func(ev Event) { events = append(events, ev) },
+	}).Export(data)
+	if err == nil {
+		t.Fatal("Expected the NaN row to abort the export")
+	}
+
+	var kinds []string
+	for _, ev := range events {
+		kinds = append(kinds, ev.Kind.String())
+	}
+	want := "[header_resolved rows_processed row_error completed]"
+	if fmt.Sprint(kinds) != want {
+		t.Fatalf("Expected events %s, got %v", want, kinds)
+	}
+	if fmt.Sprint(events[0].Columns) != "[姓名 年龄 分数]" || events[0].Sheet != "Sheet1" {
+		t.Errorf("Unexpected header event: %+v", events[0])
+	}
+	if events[2].RowIndex != 4 || events[2].Err == nil {
+		t.Errorf("Unexpected row error event: %+v", events[2])
+	}
+	if last := events[3]; last.Rows != 2 || last.Err == nil {
+		t.Errorf("Unexpected completion event: %+v", last)
+	}
+}
@@ -0,0 +1,297 @@
+package exporter
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// Format is an output file format for ExportFormats
+type Format int
+
+const (
+	// FormatXLSX is the workbook Export produces
+	FormatXLSX Format = iota
+	// FormatCSV is UTF-8 CSV with a byte order mark, so Excel detects the encoding
+	FormatCSV
+)
+
+func (f Format) String() string {
+	switch f {
+	case FormatXLSX:
+		return "xlsx"
+	case FormatCSV:
+		return "csv"
+	}
+	return "unknown"
+}
+
+// utf8BOM makes Excel open UTF-8 CSV files without mangling non-ASCII text
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// ExportFormats exports data once per requested format from a single pass over
+// the data, so every file has the same headers, column order and cell values.
+// Files are named after FileName with the format's extension. CSV files contain
+// the header and data rows only: styles, dropdowns and group title rows are
+// specific to the workbook.
+func (e *ExcelExporter[T]) ExportFormats(data []T, formats ...Format) (map[Format]*DownloadResponse, error) {
+	if len(data) == 0 && e.config.OnEmptyData == EmptyDataError {
+		return nil, fmt.Errorf("no data to export")
+	}
+
+	e = e.prepare(data)
+	layout := e.layoutRows(data)
+	writeSheet := len(data) > 0 || e.config.OnEmptyData != EmptyDataWriteNothing
+
+	outputs := make(map[Format]formatOutput, len(formats))
+	for _, format := range formats {
+		if outputs[format] != nil {
+			continue
+		}
+		var output formatOutput
+		var err error
+		switch format {
+		case FormatXLSX:
+			output, err = e.newXLSXOutput(layout, writeSheet)
+		case FormatCSV:
+			output, err = e.newCSVOutput(writeSheet)
+		default:
+			err = fmt.Errorf("unsupported format %d", format)
+		}
+		if err != nil {
+			closeOutputs(outputs)
+			return nil, fmt.Errorf("%s: %v", format, err)
+		}
+		outputs[format] = output
+	}
+	defer closeOutputs(outputs)
+
+	if writeSheet {
+		err := e.eachRow(context.Background(), e.config.SheetName, data, layout.dataRows, func(row int, cells []exportCell) error {
+			for format, output := range outputs {
+				if err := output.writeRow(row, cells); err != nil {
+					return fmt.Errorf("%s: %v", format, err)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	result := make(map[Format]*DownloadResponse, len(outputs))
+	for format, output := range outputs {
+		resp, err := output.finish()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", format, err)
+		}
+		result[format] = resp
+	}
+	return result, nil
+}
+
+// formatOutput builds the file of one format as ExportFormats streams the rows
+type formatOutput interface {
+	writeRow(row int, cells []exportCell) error
+	finish() (*DownloadResponse, error)
+	close()
+}
+
+func closeOutputs(outputs map[Format]formatOutput) {
+	for _, output := range outputs {
+		output.close()
+	}
+}
+
+type xlsxOutput[T any] struct {
+	e          *ExcelExporter[T]
+	f          *excelize.File
+	sheetName  string
+	layout     rowLayout
+	writeSheet bool
+}
+
+func (e *ExcelExporter[T]) newXLSXOutput(layout rowLayout, writeSheet bool) (*xlsxOutput[T], error) {
+	f, sheetName, err := e.newFile()
+	if err != nil {
+		return nil, err
+	}
+	if writeSheet {
+		if err := e.buildSkeleton(f, sheetName); err != nil {
+			f.Close()
+			return nil, err
+		}
+	}
+	return &xlsxOutput[T]{e: e, f: f, sheetName: sheetName, layout: layout, writeSheet: writeSheet}, nil
+}
+
+func (o *xlsxOutput[T]) writeRow(row int, cells []exportCell) error {
+	return o.e.writeRow(o.f, o.sheetName, row, cells)
+}
+
+func (o *xlsxOutput[T]) finish() (*DownloadResponse, error) {
+	if o.writeSheet {
+		if err := o.e.formatSheet(o.f, o.sheetName, o.layout); err != nil {
+			return nil, err
+		}
+	}
+	return o.e.writeResponse(o.f)
+}
+
+func (o *xlsxOutput[T]) close() {
+	_ = o.f.Close()
+}
+
+type csvOutput struct {
+	buffer   bytes.Buffer
+	w        *csv.Writer
+	record   []string
+	fileName string
+}
+
+func (e *ExcelExporter[T]) newCSVOutput(writeSheet bool) (*csvOutput, error) {
+	base := strings.TrimSuffix(e.config.FileName, filepath.Ext(e.config.FileName))
+	o := &csvOutput{fileName: base + ".csv"}
+	if !writeSheet {
+		return o, nil
+	}
+	o.buffer.Write(utf8BOM)
+	o.w = csv.NewWriter(&o.buffer)
+	o.record = make([]string, len(e.config.Headers))
+	if err := o.w.Write(e.config.Headers); err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+func (o *csvOutput) writeRow(_ int, cells []exportCell) error {
+	for colIndex, c := range cells {
+		o.record[colIndex] = csvText(c)
+	}
+	return o.w.Write(o.record)
+}
+
+func (o *csvOutput) finish() (*DownloadResponse, error) {
+	if o.w != nil {
+		o.w.Flush()
+		if err := o.w.Error(); err != nil {
+			return nil, fmt.Errorf("csv write failed: %v", err)
+		}
+	}
+
+	content := o.buffer.Bytes()
+	return &DownloadResponse{
+		FileName:    o.fileName,
+		FileSize:    int64(len(content)),
+		ContentType: "text/csv; charset=utf-8",
+		Content:     content,
+	}, nil
+}
+
+func (o *csvOutput) close() {}
+
+// csvText renders a cell the way the workbook displays it
+func csvText(c exportCell) string {
+	switch v := c.value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case time.Time:
+		format := defaultDateFormat
+		if c.kind == cellDate && c.format != "" {
+			format = c.format
+		}
+		return v.Format(dateLayout(format))
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32)
+	}
+	return fmt.Sprint(c.value)
+}
+
+// dateLayout converts an Excel date number format such as "yyyy-mm-dd hh:mm" to
+// a time layout. As in Excel, "m" and "mm" mean minutes right after an hour or
+// before a second, and months otherwise. Quoted and \-escaped text is copied,
+// bracketed sections such as locale codes are dropped.
+func dateLayout(format string) string {
+	// Only ASCII letters are lowered, so byte offsets match format
+	lower := string(bytes.Map(func(r rune) rune {
+		if 'A' <= r && r <= 'Z' {
+			return r + 'a' - 'A'
+		}
+		return r
+	}, []byte(format)))
+	twelveHour := strings.Contains(lower, "am/pm")
+	var layout strings.Builder
+	afterHour := false
+	for i := 0; i < len(format); {
+		switch {
+		case format[i] == '"':
+			end := strings.IndexByte(format[i+1:], '"')
+			if end < 0 {
+				end = len(format) - i - 1
+			}
+			layout.WriteString(format[i+1 : i+1+end])
+			i += end + 2
+			continue
+		case format[i] == '\\' && i+1 < len(format):
+			layout.WriteByte(format[i+1])
+			i += 2
+			continue
+		case format[i] == '[':
+			end := strings.IndexByte(format[i:], ']')
+			if end < 0 {
+				end = len(format) - i - 1
+			}
+			i += end + 1
+			continue
+		case strings.HasPrefix(lower[i:], "am/pm"):
+			layout.WriteString("PM")
+			i += len("am/pm")
+			continue
+		}
+
+		c := lower[i]
+		n := 1
+		for i+n < len(lower) && lower[i+n] == c {
+			n++
+		}
+		switch c {
+		case 'y':
+			layout.WriteString([]string{"06", "06", "2006"}[min(n, 3)-1])
+		case 'd':
+			layout.WriteString([]string{"2", "02", "Mon", "Monday"}[min(n, 4)-1])
+			afterHour = false
+		case 'h':
+			if twelveHour {
+				layout.WriteString([]string{"3", "03"}[min(n, 2)-1])
+			} else {
+				layout.WriteString("15")
+			}
+			afterHour = true
+		case 's':
+			layout.WriteString([]string{"5", "05"}[min(n, 2)-1])
+		case 'm':
+			if n <= 2 && (afterHour || strings.HasPrefix(strings.TrimLeft(lower[i+n:], ":. "), "s")) {
+				layout.WriteString([]string{"4", "04"}[n-1])
+			} else {
+				layout.WriteString([]string{"1", "01", "Jan", "January"}[min(n, 4)-1])
+			}
+			afterHour = false
+		default:
+			layout.WriteString(format[i : i+n])
+		}
+		i += n
+	}
+	return layout.String()
+}
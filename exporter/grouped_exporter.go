@@ -0,0 +1,161 @@
+package exporter
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// ExportGrouped partitions data by keyFn, exports each group as its own workbook
+// with the same config and returns them bundled in a zip archive. Entries are
+// named "<FileName base>_<key>.xlsx" in order of each key's first appearance;
+// keys that clash once sanitized, e.g. "a/b" and "a:b", get a " (n)" suffix.
+func (e *ExcelExporter[T]) ExportGrouped(data []T, keyFn func(T) string) (*DownloadResponse, error) {
+	var keys []string
+	groups := make(map[string][]T)
+	for _, item := range data {
+		key := keyFn(item)
+		if _, exists := groups[key]; !exists {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], item)
+	}
+
+	base := strings.TrimSuffix(e.config.FileName, filepath.Ext(e.config.FileName))
+
+	var buffer bytes.Buffer
+	zw := zip.NewWriter(&buffer)
+	used := make(map[string]bool)
+	for _, key := range keys {
+		resp, err := e.Export(groups[key])
+		if err != nil {
+			return nil, fmt.Errorf("group %s: %v", key, err)
+		}
+
+		w, err := zw.Create(fmt.Sprintf("%s_%s.xlsx", base, uniqueFileName(sanitizeFileName(key), used)))
+		if err != nil {
+			return nil, fmt.Errorf("zip create failed: %v", err)
+		}
+		if _, err := w.Write(resp.Content); err != nil {
+			return nil, fmt.Errorf("zip write failed: %v", err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("zip close failed: %v", err)
+	}
+
+	content := buffer.Bytes()
+
+	return &DownloadResponse{
+		FileName:    base + ".zip",
+		FileSize:    int64(len(content)),
+		ContentType: "application/zip",
+		Content:     content,
+	}, nil
+}
+
+// sanitizeFileName replaces characters that are not allowed in file names on common platforms
+func sanitizeFileName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`/\:*?"<>|`, r) || r < 0x20 {
+			return '_'
+		}
+		return r
+	}, strings.TrimSpace(name))
+	if name == "" {
+		return "_"
+	}
+	return name
+}
+
+// uniqueFileName suffixes name with " (n)" until it is unique case-insensitively,
+// as file systems on Windows and macOS compare names
+func uniqueFileName(name string, used map[string]bool) string {
+	candidate := name
+	for n := 2; used[strings.ToLower(candidate)]; n++ {
+		candidate = fmt.Sprintf("%s (%d)", name, n)
+	}
+	used[strings.ToLower(candidate)] = true
+	return candidate
+}
+
+// ExportGroupedSheets partitions data by keyFn and writes each group to its own
+// sheet of a single workbook. nameTemplate may contain "{key}", which is replaced
+// by the group key, e.g. "Sales - {key}"; an empty template uses the key itself.
+// Resulting names are made valid and unique per Excel's sheet naming rules.
+func (e *ExcelExporter[T]) ExportGroupedSheets(data []T, keyFn func(T) string, nameTemplate string) (*DownloadResponse, error) {
+	if nameTemplate == "" {
+		nameTemplate = "{key}"
+	}
+
+	var keys []string
+	groups := make(map[string][]T)
+	for _, item := range data {
+		key := keyFn(item)
+		if _, exists := groups[key]; !exists {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], item)
+	}
+	if len(keys) == 0 {
+		return e.Export(nil)
+	}
+
+	f := excelize.NewFile()
+	used := make(map[string]bool)
+	if len(e.config.Legend) > 0 {
+		used[strings.ToLower(e.config.LegendSheetName)] = true
+	}
+	for _, key := range keys {
+		sheetName := uniqueSheetName(sanitizeSheetName(strings.ReplaceAll(nameTemplate, "{key}", key)), used)
+		if err := claimSheet(f, sheetName); err != nil {
+			return nil, err
+		}
+
+		group := groups[key]
+		if err := e.prepare(group).buildSheet(context.Background(), f, sheetName, group); err != nil {
+			return nil, fmt.Errorf("sheet %s: %v", sheetName, err)
+		}
+	}
+
+	return e.writeResponse(f)
+}
+
+// sanitizeSheetName applies Excel's sheet name rules: no []:*?/\ characters,
+// no leading or trailing apostrophe, at most 31 characters and not empty
+func sanitizeSheetName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`[]:*?/\`, r) {
+			return '_'
+		}
+		return r
+	}, strings.TrimSpace(name))
+	name = strings.Trim(name, "'")
+	if runes := []rune(name); len(runes) > 31 {
+		name = string(runes[:31])
+	}
+	if name == "" || strings.EqualFold(name, "History") {
+		name = "Sheet_" + name
+	}
+	return name
+}
+
+// uniqueSheetName suffixes name with " (n)" until it is unique case-insensitively, keeping 31 characters
+func uniqueSheetName(name string, used map[string]bool) string {
+	candidate := name
+	for n := 2; used[strings.ToLower(candidate)]; n++ {
+		suffix := fmt.Sprintf(" (%d)", n)
+		runes := []rune(name)
+		if len(runes)+len(suffix) > 31 {
+			runes = runes[:31-len(suffix)]
+		}
+		candidate = string(runes) + suffix
+	}
+	used[strings.ToLower(candidate)] = true
+	return candidate
+}
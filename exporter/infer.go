@@ -0,0 +1,163 @@
+package exporter
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// columnKind is the cell type inferred for a column of loosely-typed (map) data
+type columnKind int
+
+const (
+	columnString columnKind = iota
+	columnNumber
+	columnDate
+)
+
+var inferDateLayouts = []string{"2006-01-02 15:04:05", "2006-01-02", "2006/01/02"}
+
+// mapKeys returns the sorted union of keys across maps with string-kinded keys
+func mapKeys(maps []reflect.Value) []string {
+	seen := make(map[string]bool)
+	var keys []string
+	for _, v := range maps {
+		if v.Kind() == reflect.Ptr {
+			v = v.Elem()
+		}
+		if v.Kind() != reflect.Map || v.Type().Key().Kind() != reflect.String {
+			continue
+		}
+		for _, key := range v.MapKeys() {
+			name := key.String()
+			if !seen[name] {
+				seen[name] = true
+				keys = append(keys, name)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// inferColumnKinds classifies each header by its non-empty values: all numbers,
+// all dates, or anything else as plain strings
+func inferColumnKinds[T any](data []T, headers []string) map[string]columnKind {
+	kinds := make(map[string]columnKind)
+	for _, header := range headers {
+		allNumbers, allDates, seen := true, true, false
+		for _, item := range data {
+			value, ok := mapEntry(reflect.ValueOf(item), header)
+			if !ok {
+				continue
+			}
+			raw := derefValue(value)
+			if raw == nil {
+				continue
+			}
+			if s, isStr := raw.(string); isStr && strings.TrimSpace(s) == "" {
+				continue
+			}
+			seen = true
+			if !isNumberValue(raw) {
+				allNumbers = false
+			}
+			if !isDateValue(raw) {
+				allDates = false
+			}
+			if !allNumbers && !allDates {
+				break
+			}
+		}
+
+		switch {
+		case !seen:
+			kinds[header] = columnString
+		case allNumbers:
+			kinds[header] = columnNumber
+		case allDates:
+			kinds[header] = columnDate
+		default:
+			kinds[header] = columnString
+		}
+	}
+	return kinds
+}
+
+// mapEntry looks up key in a map value with string-kinded keys
+func mapEntry(m reflect.Value, key string) (reflect.Value, bool) {
+	if m.Kind() == reflect.Ptr {
+		m = m.Elem()
+	}
+	if m.Kind() != reflect.Map || m.Type().Key().Kind() != reflect.String {
+		return reflect.Value{}, false
+	}
+	value := m.MapIndex(reflect.ValueOf(key).Convert(m.Type().Key()))
+	return value, value.IsValid()
+}
+
+func derefValue(v reflect.Value) any {
+	for v.Kind() == reflect.Interface || v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	return v.Interface()
+}
+
+func isNumberValue(v any) bool {
+	switch rv := reflect.ValueOf(v); rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	case reflect.String:
+		_, err := strconv.ParseFloat(strings.TrimSpace(rv.String()), 64)
+		return err == nil
+	}
+	return false
+}
+
+func isDateValue(v any) bool {
+	if _, ok := v.(time.Time); ok {
+		return true
+	}
+	s, ok := v.(string)
+	if !ok {
+		return false
+	}
+	_, ok = parseInferredDate(s)
+	return ok
+}
+
+func parseInferredDate(s string) (time.Time, bool) {
+	s = strings.TrimSpace(s)
+	for _, layout := range inferDateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// coerceInferred converts a cell value to the Go type matching its inferred column kind
+func coerceInferred(kind columnKind, value any) any {
+	s, ok := value.(string)
+	if !ok {
+		return value
+	}
+	switch kind {
+	case columnNumber:
+		if f, err := strconv.ParseFloat(strings.TrimSpace(s), 64); err == nil {
+			return f
+		}
+	case columnDate:
+		if t, ok := parseInferredDate(s); ok {
+			return t
+		}
+	}
+	return value
+}
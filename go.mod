@@ -2,9 +2,14 @@ module github.com/enterShuIoT/impex
 
 go 1.24.0
 
-require github.com/xuri/excelize/v2 v2.10.0
+require (
+	github.com/extrame/xls v0.0.1
+	github.com/xuri/excelize/v2 v2.10.0
+	golang.org/x/text v0.30.0
+)
 
 require (
+	github.com/extrame/ole2 v0.0.0-20160812065207-d69429661ad7 // indirect
 	github.com/richardlehane/mscfb v1.0.4 // indirect
 	github.com/richardlehane/msoleps v1.0.4 // indirect
 	github.com/tiendc/go-deepcopy v1.7.1 // indirect
@@ -12,5 +17,4 @@ require (
 	github.com/xuri/nfp v0.0.2-0.20250530014748-2ddeb826f9a9 // indirect
 	golang.org/x/crypto v0.43.0 // indirect
 	golang.org/x/net v0.46.0 // indirect
-	golang.org/x/text v0.30.0 // indirect
 )
@@ -0,0 +1,83 @@
+package importer
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// columnGroupPattern returns the header pattern of a "pattern:<regexp>" or
+// "prefix:<text>" tag option, which turns a slice field into a column group
+func columnGroupPattern(options []string) (string, bool) {
+	for _, option := range options {
+		option = strings.TrimSpace(option)
+		if pattern, ok := strings.CutPrefix(option, "pattern:"); ok {
+			return pattern, true
+		}
+		if prefix, ok := strings.CutPrefix(option, "prefix:"); ok {
+			return "^" + regexp.QuoteMeta(prefix), true
+		}
+	}
+	return "", false
+}
+
+// addColumnGroup registers a slice field collecting the headers matching pattern
+func (importer *ExcelImporter[T]) addColumnGroup(fieldName, pattern string) {
+	regex, err := regexp.Compile(pattern)
+	if err != nil {
+		if importer.configErr == nil {
+			importer.configErr = fmt.Errorf("invalid config: column group %s: %v", fieldName, err)
+		}
+		return
+	}
+	if importer.columnGroups == nil {
+		importer.columnGroups = make(map[string]*regexp.Regexp)
+	}
+	importer.columnGroups[fieldName] = regex
+}
+
+// fillColumnGroups fills each column group with its matching cells in column
+// order. Columns taken by regular fields are skipped, and claimed columns are
+// marked used so the dynamic field does not capture them again. Empty cells
+// keep their position as the element's zero value.
+func (importer *ExcelImporter[T]) fillColumnGroups(val reflect.Value, row []string, rowIndex int, columnIndexMap map[string]int, usedColumns map[int]bool) error {
+	for _, fieldName := range slices.Sorted(maps.Keys(importer.columnGroups)) {
+		field := val.FieldByName(fieldName)
+		if !field.IsValid() || !field.CanSet() || field.Kind() != reflect.Slice {
+			return fmt.Errorf("column group field %s must be a slice", fieldName)
+		}
+
+		pattern := importer.columnGroups[fieldName]
+		var indexes []int
+		names := make(map[int]string)
+		for colName, colIdx := range columnIndexMap {
+			if !usedColumns[colIdx] && pattern.MatchString(colName) {
+				indexes = append(indexes, colIdx)
+				names[colIdx] = colName
+			}
+		}
+		sort.Ints(indexes)
+
+		values := reflect.MakeSlice(field.Type(), len(indexes), len(indexes))
+		elemType := reflect.StructField{Name: fieldName, Type: field.Type().Elem()}
+		for i, colIdx := range indexes {
+			usedColumns[colIdx] = true
+			var cellValue string
+			if colIdx < len(row) {
+				cellValue = strings.TrimSpace(row[colIdx])
+			}
+			if cellValue == "" || importer.isNullValue(cellValue) {
+				continue
+			}
+			if err := importer.convertAndSetField(values.Index(i), elemType, cellValue, importer.storedNumber(rowIndex, colIdx)); err != nil {
+				return fmt.Errorf("field %s column %s conversion failed: %v", fieldName, names[colIdx], err)
+			}
+		}
+		field.Set(values)
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package importer
+
+import (
+	"strings"
+)
+
+// CombineSpec fills one field from several columns joined together, e.g. a full
+// name from first and last name columns. It is the import-side counterpart of
+// the exporter's ComputedColumns.
+type CombineSpec struct {
+	Columns []string // Headers joined in this order
+	Sep     string   // Placed between non-empty cells, e.g. " "
+}
+
+// combineColumn names a combined field's source columns in messages
+func (spec CombineSpec) combineColumn() string {
+	return strings.Join(spec.Columns, "+")
+}
+
+// hasAnyColumn reports whether at least one of the spec's columns is in the header
+func (spec CombineSpec) hasAnyColumn(columnIndexMap map[string]int) bool {
+	for _, excelCol := range spec.Columns {
+		if _, exists := columnIndexMap[excelCol]; exists {
+			return true
+		}
+	}
+	return false
+}
+
+// combineCells joins the spec's non-empty cells with Sep and marks their columns
+// used. Absent columns contribute nothing.
+func combineCells(spec CombineSpec, row []string, columnIndexMap map[string]int, usedColumns map[int]bool) string {
+	parts := make([]string, 0, len(spec.Columns))
+	for _, excelCol := range spec.Columns {
+		colIndex, exists := columnIndexMap[excelCol]
+		if !exists {
+			continue
+		}
+		usedColumns[colIndex] = true
+		if colIndex < len(row) {
+			if cell := strings.TrimSpace(row[colIndex]); cell != "" {
+				parts = append(parts, cell)
+			}
+		}
+	}
+	return strings.Join(parts, spec.Sep)
+}
@@ -0,0 +1,59 @@
+package importer
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// withComments returns a copy of the importer holding the sheet's cell comments
+// when CommentFields is set, so they are read once per import
+func (importer *ExcelImporter[T]) withComments(f *excelize.File, sheetName string) (*ExcelImporter[T], error) {
+	if len(importer.config.CommentFields) == 0 {
+		return importer, nil
+	}
+	if f == nil {
+		return nil, fmt.Errorf("comment fields are not supported for legacy .xls files")
+	}
+
+	comments, err := f.GetComments(sheetName)
+	if err != nil {
+		return nil, fmt.Errorf("read comments failed: %v", err)
+	}
+	cellComments := make(map[string]string, len(comments))
+	for _, comment := range comments {
+		var text strings.Builder
+		text.WriteString(comment.Text)
+		for _, run := range comment.Paragraph {
+			text.WriteString(run.Text)
+		}
+		cellComments[comment.Cell] = strings.TrimSpace(text.String())
+	}
+
+	call := *importer
+	call.cellComments = cellComments
+	return &call, nil
+}
+
+// fillComments sets each CommentFields field to the comment on its column's cell
+// in the row, or "" when the cell has none or the column is absent
+func (importer *ExcelImporter[T]) fillComments(val reflect.Value, rowIndex int, columnIndexMap map[string]int) error {
+	for fieldName, excelCol := range importer.config.CommentFields {
+		field := val.FieldByName(fieldName)
+		if !field.IsValid() || field.Kind() != reflect.String {
+			return fmt.Errorf("comment field %s must be a string", fieldName)
+		}
+		colIndex, exists := columnIndexMap[excelCol]
+		if !exists {
+			continue
+		}
+		cell, err := excelize.CoordinatesToCellName(colIndex+importer.config.StartColumn, rowIndex)
+		if err != nil {
+			return err
+		}
+		field.SetString(importer.cellComments[cell])
+	}
+	return nil
+}
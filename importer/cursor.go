@@ -0,0 +1,107 @@
+package importer
+
+import (
+	"context"
+	"fmt"
+)
+
+// Cursor is a pull-based alternative to ImportStream, modelled after database/sql.Rows:
+//
+//	c, err := imp.CursorLocal("users.xlsx")
+//	defer c.Close()
+//	for c.Next() {
+//		row, err := c.Scan()
+//	}
+//	if err := c.Err(); err != nil { ... }
+//
+// Row-level conversion and validation errors are returned by Scan and do not stop the
+// cursor. Errors that end the import (missing columns, unreadable rows) are reported by Err.
+type Cursor[T any] struct {
+	wb      *workbook
+	rows    rowIterator
+	scanner *rowScanner[T]
+	current ImportResult[T]
+	err     error
+	closed  bool
+}
+
+// Cursor downloads the file and returns a cursor over its data rows
+func (importer *ExcelImporter[T]) Cursor(url string) (*Cursor[T], error) {
+	body, _, err := importer.download(context.Background(), url)
+	if err != nil {
+		return nil, fmt.Errorf("download failed: %w", err)
+	}
+	defer body.Close()
+	wb, err := importer.openWorkbookReader(body)
+	if err != nil {
+		return nil, fmt.Errorf("open excel failed: %w", err)
+	}
+	return importer.newCursor(wb)
+}
+
+// CursorLocal is the local file variant of Cursor
+func (importer *ExcelImporter[T]) CursorLocal(filePath string) (*Cursor[T], error) {
+	wb, err := importer.openWorkbookLocal(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("open excel failed: %w", err)
+	}
+	return importer.newCursor(wb)
+}
+
+func (importer *ExcelImporter[T]) newCursor(wb *workbook) (*Cursor[T], error) {
+	scanner, err := importer.newRowScanner(wb)
+	if err != nil {
+		_ = wb.Close()
+		return nil, err
+	}
+	return &Cursor[T]{
+		wb:      wb,
+		rows:    scanner.rows,
+		scanner: scanner,
+	}, nil
+}
+
+// Next advances to the next data row, returning false when the sheet is exhausted or a fatal error occurred
+func (c *Cursor[T]) Next() bool {
+	if c.closed || c.err != nil {
+		return false
+	}
+	res, ok := c.scanner.next()
+	if !ok {
+		return false
+	}
+	if c.scanner.done && res.Error != nil {
+		c.err = res.Error
+		return false
+	}
+	c.current = res
+	return true
+}
+
+// Scan returns the current row and its conversion or validation error, if any
+func (c *Cursor[T]) Scan() (T, error) {
+	return c.current.Data, c.current.Error
+}
+
+// RowIndex returns the 1-based sheet row number of the current row
+func (c *Cursor[T]) RowIndex() int {
+	return c.current.RowIndex
+}
+
+// Err returns the error, if any, that ended the iteration
+func (c *Cursor[T]) Err() error {
+	return c.err
+}
+
+// Close releases the underlying file. It is safe to call more than once.
+func (c *Cursor[T]) Close() error {
+	if c.closed {
+		return nil
+	}
+	c.closed = true
+	if err := c.rows.Close(); err != nil {
+		_ = c.wb.Close()
+		return err
+	}
+	return c.wb.Close()
+}
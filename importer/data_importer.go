@@ -1,11 +1,114 @@
 package importer
 
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrNoSheets is returned for a workbook without any worksheet
+var ErrNoSheets = errors.New("excel file has no sheets")
+
+// ErrTooManyErrors ends a scan that reached MaxErrors row errors
+var ErrTooManyErrors = errors.New("too many errors")
+
+// ErrUnsupportedKind fails a row whose field is of a kind cells cannot convert
+// to, e.g. chan or complex128, unless LenientKinds is set
+var ErrUnsupportedKind = errors.New("unsupported kind")
+
 type ImportResult[T any] struct {
 	RowIndex int
 	Data     T
 	Error    error
 }
 
+// RowError describes a problem found on a specific row. RowIndex is 0 for file-level errors.
+type RowError struct {
+	RowIndex int
+	Err      error
+}
+
+func (e RowError) Error() string {
+	if e.RowIndex == 0 {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("row %d error: %v", e.RowIndex, e.Err)
+}
+
+func (e RowError) Unwrap() error {
+	return e.Err
+}
+
+// maxFoundColumns limits how many detected headers MissingColumnsError prints
+const maxFoundColumns = 10
+
+// MissingColumnsError reports required columns absent from the header together
+// with the headers that were detected, which makes a wrong sheet easy to spot
+type MissingColumnsError struct {
+	Missing []string
+	Found   []string // Detected headers in sheet order
+}
+
+func (e *MissingColumnsError) Error() string {
+	found := e.Found
+	more := ""
+	if len(found) > maxFoundColumns {
+		more = fmt.Sprintf(" and %d more", len(found)-maxFoundColumns)
+		found = found[:maxFoundColumns]
+	}
+	return fmt.Sprintf("missing columns: %s (found: %s%s)", strings.Join(e.Missing, ", "), strings.Join(found, ", "), more)
+}
+
+// SheetNotFoundError reports a configured SheetName that is not in the workbook
+type SheetNotFoundError struct {
+	Sheet     string
+	Available []string // Sheet names in workbook order
+}
+
+func (e *SheetNotFoundError) Error() string {
+	return fmt.Sprintf("sheet %s does not exist (available: %s)", e.Sheet, strings.Join(e.Available, ", "))
+}
+
+// NotSpreadsheetError reports input that is not an xlsx or xls file at all, such
+// as an HTML login page served instead of the requested download
+type NotSpreadsheetError struct {
+	ContentType string // Sniffed or declared MIME type, "" for an empty file
+}
+
+func (e *NotSpreadsheetError) Error() string {
+	if e.ContentType == "" {
+		return "not a spreadsheet: file is empty"
+	}
+	return fmt.Sprintf("not a spreadsheet: expected xlsx but got %s", e.ContentType)
+}
+
+// CorruptFileError reports input that has a spreadsheet signature but cannot be read
+type CorruptFileError struct {
+	Err error
+}
+
+func (e *CorruptFileError) Error() string {
+	return fmt.Sprintf("spreadsheet is corrupt: %v", e.Err)
+}
+
+func (e *CorruptFileError) Unwrap() error {
+	return e.Err
+}
+
+// Warning is a non-fatal issue noticed during import, e.g. a missing optional column
+type Warning struct {
+	RowIndex int
+	Column   string
+	Message  string
+}
+
+func (w Warning) String() string {
+	if w.Column != "" {
+		return fmt.Sprintf("row %d column %s: %s", w.RowIndex, w.Column, w.Message)
+	}
+	return fmt.Sprintf("row %d: %s", w.RowIndex, w.Message)
+}
+
 type DataImporter[T any] interface {
 	Import(path string) ([]T, error)
 	ImportStream(path string) <-chan ImportResult[T]
@@ -0,0 +1,97 @@
+package importer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Discriminator routes each data row to a different struct type by the value in
+// one column, for sheets mixing several record kinds. Use it with an importer whose
+// T is any, or an interface every variant implements:
+//
+//	imp := NewExcelImporter(&ExcelImportConfig[any]{
+//		Discriminator: &Discriminator{
+//			Column: "Type",
+//			Variants: map[string]Variant{
+//				"login":    VariantOf(&ExcelImportConfig[LoginEvent]{}),
+//				"purchase": VariantOf(&ExcelImportConfig[PurchaseEvent]{}),
+//			},
+//		},
+//	})
+//
+// Rows are then returned as LoginEvent or PurchaseEvent values. A row whose
+// discriminator value has no variant fails with a row error.
+type Discriminator struct {
+	Column   string             // Header of the discriminator column
+	Variants map[string]Variant // Column value -> row type
+}
+
+// Variant parses the rows of one discriminator value, see VariantOf
+type Variant interface {
+	checkColumns(columnIndexMap map[string]int) error
+	parse(row []string, rowIndex int, columnIndexMap map[string]int) (any, error)
+}
+
+type variant[V any] struct {
+	importer *ExcelImporter[V]
+}
+
+// VariantOf parses rows into V using config's mappings, converters, defaults and
+// validators. Sheet layout options such as SheetName and StartRow are taken from
+// the outer importer and ignored here.
+func VariantOf[V any](config *ExcelImportConfig[V]) Variant {
+	return variant[V]{importer: NewExcelImporter(config)}
+}
+
+func (v variant[V]) checkColumns(columnIndexMap map[string]int) error {
+	return v.importer.checkMissingColumns(columnIndexMap)
+}
+
+func (v variant[V]) parse(row []string, rowIndex int, columnIndexMap map[string]int) (any, error) {
+	return v.importer.parseRow(row, rowIndex, columnIndexMap)
+}
+
+// checkColumns requires the discriminator column and every variant's columns
+func (d *Discriminator) checkColumns(columnIndexMap map[string]int) error {
+	if _, exists := columnIndexMap[d.Column]; !exists {
+		return &MissingColumnsError{Missing: []string{d.Column}, Found: foundColumns(columnIndexMap)}
+	}
+
+	values := make([]string, 0, len(d.Variants))
+	for value := range d.Variants {
+		values = append(values, value)
+	}
+	sort.Strings(values)
+	for _, value := range values {
+		if err := d.Variants[value].checkColumns(columnIndexMap); err != nil {
+			return fmt.Errorf("variant %s: %w", value, err)
+		}
+	}
+	return nil
+}
+
+// parseVariant parses a row with the variant selected by its discriminator cell
+func (importer *ExcelImporter[T]) parseVariant(row []string, rowIndex int, columnIndexMap map[string]int) (T, error) {
+	var instance T
+	d := importer.config.Discriminator
+
+	var value string
+	if idx := columnIndexMap[d.Column]; idx < len(row) {
+		value = strings.TrimSpace(row[idx])
+	}
+	v, ok := d.Variants[value]
+	if !ok {
+		return instance, fmt.Errorf("no variant for %s %q", d.Column, value)
+	}
+
+	parsed, err := v.parse(row, rowIndex, columnIndexMap)
+	if err != nil {
+		return instance, err
+	}
+	instance, ok = parsed.(T)
+	if !ok {
+		return instance, fmt.Errorf("variant %s: %T is not assignable to the importer's row type", value, parsed)
+	}
+	return instance, nil
+}
@@ -0,0 +1,99 @@
+package importer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"time"
+)
+
+// RetryPolicy retries URL downloads on transient network errors and status codes
+type RetryPolicy struct {
+	MaxAttempts     int           // Total attempts including the first, below 2 means no retries
+	Backoff         time.Duration // Wait before the first retry, doubled after each one
+	MaxBackoff      time.Duration // Upper bound for the wait, 0 means unbounded
+	RetryableStatus []int         // Status codes worth retrying, nil means 408, 429 and 5xx
+}
+
+// statusError is a download answered with a status other than 200
+type statusError struct {
+	code int
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("status code: %d", e.code)
+}
+
+// retryable reports whether err is worth another attempt
+func (p *RetryPolicy) retryable(err error) bool {
+	var notSpreadsheet *NotSpreadsheetError
+	if errors.As(err, &notSpreadsheet) {
+		return false
+	}
+	var status *statusError
+	if !errors.As(err, &status) {
+		return transientNetworkError(err)
+	}
+	if p.RetryableStatus != nil {
+		return slices.Contains(p.RetryableStatus, status.code)
+	}
+	return status.code == http.StatusRequestTimeout || status.code == http.StatusTooManyRequests || status.code >= 500
+}
+
+// transientNetworkError reports whether a failed request may succeed when sent
+// again: timeouts, refused or reset connections and connections closed early.
+// Malformed URLs, unknown hosts and TLS failures are permanent; cancellation is
+// handled by the caller.
+func transientNetworkError(err error) bool {
+	// http.Client wraps every failure in a *url.Error, which is itself a net.Error
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		err = urlErr.Err
+	}
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return !dnsErr.IsNotFound
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+	return errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// download fetches url, retrying according to the Retry policy
+func (importer *ExcelImporter[T]) download(ctx context.Context, url string) (io.ReadCloser, string, error) {
+	policy := importer.config.Retry
+	if policy == nil || policy.MaxAttempts < 2 {
+		return downloadFromUrl(ctx, url)
+	}
+
+	backoff := policy.Backoff
+	for attempt := 1; ; attempt++ {
+		body, fileName, err := downloadFromUrl(ctx, url)
+		if err == nil {
+			return body, fileName, nil
+		}
+		if ctx.Err() != nil || attempt >= policy.MaxAttempts || !policy.retryable(err) {
+			if attempt > 1 {
+				return nil, "", fmt.Errorf("%w (after %d attempts)", err, attempt)
+			}
+			return nil, "", err
+		}
+
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return nil, "", ctx.Err()
+		}
+		backoff *= 2
+		if policy.MaxBackoff > 0 && backoff > policy.MaxBackoff {
+			backoff = policy.MaxBackoff
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package importer
+
+import "time"
+
+// EventKind identifies a point in the import lifecycle reported to OnEvent
+type EventKind int
+
+const (
+	// EventFileOpened fires once the workbook has been opened and decoded
+	EventFileOpened EventKind = iota
+	// EventHeaderResolved fires after the header row passed the column checks
+	EventHeaderResolved
+	// EventRowsProcessed fires every ProgressInterval data rows
+	EventRowsProcessed
+	// EventRowError fires for each row that failed conversion or validation
+	EventRowError
+	// EventCompleted fires once when the sheet is done, with Err set if the import was aborted
+	EventCompleted
+)
+
+func (k EventKind) String() string {
+	switch k {
+	case EventFileOpened:
+		return "file_opened"
+	case EventHeaderResolved:
+		return "header_resolved"
+	case EventRowsProcessed:
+		return "rows_processed"
+	case EventRowError:
+		return "row_error"
+	case EventCompleted:
+		return "completed"
+	}
+	return "unknown"
+}
+
+// Event is an observability record passed to OnEvent
+type Event struct {
+	Kind     EventKind
+	RowIndex int           // Sheet row the event refers to, 0 if none
+	Rows     int           // Data rows processed so far
+	Errors   int           // Rows that failed so far
+	Columns  []string      // Header cells, for EventHeaderResolved
+	Err      error         // Row error for EventRowError, fatal error for EventCompleted
+	Elapsed  time.Duration // Time since the sheet scan started
+}
+
+// importStats tracks one sheet scan for progress reporting and events
+type importStats struct {
+	started   time.Time
+	processed int
+	errors    int
+	err       error
+}
+
+// newImportStats starts the clock only when someone is listening
+func (importer *ExcelImporter[T]) newImportStats() importStats {
+	if importer.config.OnEvent == nil {
+		return importStats{}
+	}
+	return importStats{started: time.Now()}
+}
+
+func (importer *ExcelImporter[T]) emit(ev Event, stats importStats) {
+	if importer.config.OnEvent == nil {
+		return
+	}
+	ev.Rows = stats.processed
+	ev.Errors = stats.errors
+	if !stats.started.IsZero() {
+		ev.Elapsed = time.Since(stats.started)
+	}
+	importer.config.OnEvent(ev)
+}
+
+// fail reports an aborted batch import and passes err through
+func (importer *ExcelImporter[T]) fail(stats importStats, err error) error {
+	importer.emit(Event{Kind: EventCompleted, Err: err}, stats)
+	return err
+}
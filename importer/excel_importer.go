@@ -1,30 +1,183 @@
 package importer
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"maps"
+	"math"
 	"net/http"
 	"path/filepath"
 	"reflect"
 	"regexp"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
+	"unicode"
 
 	"github.com/xuri/excelize/v2"
+	"golang.org/x/text/unicode/norm"
 )
 
+// MissingColumnPolicy controls what happens when a mapped column is absent from the header
+type MissingColumnPolicy int
+
+const (
+	// MissingColumnError rejects the file (default)
+	MissingColumnError MissingColumnPolicy = iota
+	// MissingColumnDefault fills the field from DefaultValues
+	MissingColumnDefault
+	// MissingColumnIgnore leaves the field at its zero value
+	MissingColumnIgnore
+)
+
+func (p MissingColumnPolicy) String() string {
+	switch p {
+	case MissingColumnError:
+		return "error"
+	case MissingColumnDefault:
+		return "default"
+	case MissingColumnIgnore:
+		return "ignore"
+	}
+	return "unknown"
+}
+
+// EmptyCellPolicy controls what happens when a mapped column is present but the cell is empty
+type EmptyCellPolicy int
+
+const (
+	// EmptyCellDefault fills the field from DefaultValues (default)
+	EmptyCellDefault EmptyCellPolicy = iota
+	// EmptyCellError fails the row
+	EmptyCellError
+	// EmptyCellZero leaves the field at its zero value, ignoring DefaultValues
+	EmptyCellZero
+)
+
+func (p EmptyCellPolicy) String() string {
+	switch p {
+	case EmptyCellDefault:
+		return "default"
+	case EmptyCellError:
+		return "error"
+	case EmptyCellZero:
+		return "zero"
+	}
+	return "unknown"
+}
+
+// TrailingCellPolicy controls what happens to non-empty cells right of the last header column
+type TrailingCellPolicy int
+
+const (
+	// TrailingCellIgnore drops them (default)
+	TrailingCellIgnore TrailingCellPolicy = iota
+	// TrailingCellError fails the row, as extra cells often mean broken quoting in a converted CSV
+	TrailingCellError
+	// TrailingCellCapture stores them in TrailingField
+	TrailingCellCapture
+)
+
+func (p TrailingCellPolicy) String() string {
+	switch p {
+	case TrailingCellIgnore:
+		return "ignore"
+	case TrailingCellError:
+		return "error"
+	case TrailingCellCapture:
+		return "capture"
+	}
+	return "unknown"
+}
+
+// Compression selects how the input stream is decompressed before parsing
+type Compression int
+
+const (
+	// CompressionAuto detects gzip input by its magic bytes (default)
+	CompressionAuto Compression = iota
+	// CompressionNone never decompresses
+	CompressionNone
+	// CompressionGzip always decompresses with gzip
+	CompressionGzip
+)
+
+// DefaultSpec is a per-field default with control over when it applies
+type DefaultSpec struct {
+	Value any
+	// EmptyOnly applies the default only to empty cells of a present column; a
+	// missing column is then always an error, whatever OnMissingColumn says
+	EmptyOnly bool
+}
+
+// RowConverter converts a field's cell with access to the whole row; cols maps
+// header names to indexes in row, e.g. to read an amount's currency column
+type RowConverter func(cell string, row []string, cols map[string]int) (any, error)
+
 // ExcelImportConfig configuration for Excel import
 type ExcelImportConfig[T any] struct {
-	SheetName        string
-	StartRow         int
-	HeaderRow        int
-	FieldMappings    map[string]string            // Excel Column -> Struct Field
-	DefaultValues    map[string]any
-	Validators       map[string]func(any) error
-	CustomConverters map[string]func(string) (any, error)
-	SkipRows         map[int]bool
-	RowHook          func(*T, []string, map[string]int) error
+	SheetName          string
+	StartRow           int
+	HeaderRow          int
+	StartColumn        int               // 1-based first column of the table, cells left of it are ignored; defaults to 1
+	KeyColumns         int               // Number of leading columns, from StartColumn, that fill mapped fields; every column right of them goes to the dynamic field, e.g. a forecast matrix. 0 maps by header alone
+	DynamicKeyColumn   string            // Column whose cell keys the dynamic field instead of the headers, for long-format sheets: each row adds one entry holding DynamicValueColumn's cell
+	DynamicValueColumn string            // Column holding the dynamic entry's value when DynamicKeyColumn is set
+	FieldMappings      map[string]string // Excel Column -> Struct Field
+	HeaderAliases      map[string]string // Header as found in the file -> header used for matching, e.g. {"Amuont": "Amount"}
+	ColumnGroups       map[string]string // Slice field -> regexp; matching headers fill the slice in column order, same as tag "pattern:" or "prefix:"
+	DefaultValues      map[string]any
+	DefaultSpecs       map[string]DefaultSpec // Field -> default, takes precedence over DefaultValues
+	Validators         map[string]func(any) error
+	CustomConverters   map[string]func(string) (any, error)
+	RowConverters      map[string]RowConverter        // Field -> converter that can read sibling cells, takes precedence over CustomConverters
+	Lookups            map[string]Lookup              // Field -> reference table on another sheet its value is looked up in
+	CombineColumns     map[string]CombineSpec         // Field -> columns joined into one cell before conversion, e.g. first and last name
+	CommentFields      map[string]string              // String field -> column whose cell comments (notes) it receives, same as tag excel:"Status,comment"; not supported for legacy .xls
+	PreTransforms      map[string]func(string) string // Field -> cell rewrite applied before empty checks and conversion
+	TrimSets           map[string]string              // Field -> characters trimmed from both ends of the cell before PreTransforms, same as tag "trimset:$¥€"
+	SkipRows           map[int]bool
+	CommentPrefix      string // Data rows whose first non-empty cell starts with it are skipped and not counted toward MaxRows
+	RowHook            func(*T, []string, map[string]int) error
+	FilterPredicate    func(*T) bool // Keeps only rows it accepts; runs after a row parsed and validated, so rejected rows are not errors
+	OnMissingColumn    MissingColumnPolicy
+	OnEmptyCell        EmptyCellPolicy
+	OnTrailingCells    TrailingCellPolicy
+	TrailingField      string         // []string field receiving the cells right of the header for TrailingCellCapture, same as tag excel:"trailing"
+	MaxCellLength      int            // Max bytes per cell, 0 means unlimited
+	MaxColumns         int            // Max columns per row, 0 means unlimited
+	MaxRows            int            // Max rows per sheet, 0 means unlimited
+	MaxErrors          int            // Row errors streams, cursors and Validate report before stopping with ErrTooManyErrors, 0 means unlimited
+	SanitizeCells      bool           // Strip control/format characters and NFC-normalize cells before use
+	Location           *time.Location // Zone for parsing time.Time cells, nil means UTC
+	RowNumField        string         // Struct field receiving the 1-based sheet row number, same as tag excel:"rownum"
+	RawRowField        string         // []string field receiving a copy of the row's cells, same as tag excel:"rawrow"
+	OnProgress         func(rowsProcessed int)
+	ProgressInterval   int         // Data rows between OnProgress calls and EventRowsProcessed events, defaults to 100
+	OnEvent            func(Event) // Lifecycle hook for logging and metrics, nil disables it
+	Compression        Compression
+	OptionalColumns    []string // Columns that may be absent without failing, same as tag "optional"
+	OnWarning          func(Warning)
+	RawField           string          // map[string]string field receiving each mapped field's original cell text, same as tag excel:"raw"
+	InputPassword      string          // Password for encrypted workbooks
+	RawCellValues      bool            // Read unformatted cell values, e.g. "1234" instead of "$1,234.00"
+	PercentFields      map[string]bool // Fields holding whole percents read from 0-1 fractions, e.g. 0.45 -> 45, same as tag "percent100"
+	Discriminator      *Discriminator  // Parse each row into a type chosen by one column, T must be any or an interface
+	BoolValues         map[string]bool // Cell text -> bool, compared case-insensitively so texts differing only in case must agree; nil uses true/false, 1/0, 是/否, yes/no, y/n
+	LenientBools       bool            // Read unrecognized bool cells as false instead of failing the row
+	LenientKinds       bool            // Best effort for non-critical fields: string fields take any converter result via fmt.Sprint, interface fields the cell text, and fields of other unsupported kinds stay zero with a warning instead of failing the row
+	UnmergeValues      bool            // Copy each merged range's value into all of its cells, not supported for legacy .xls
+	RecalcFormulas     bool            // Evaluate formula cells instead of using cached results, slow on large sheets; not supported for legacy .xls
+	Retry              *RetryPolicy    // Retries for URL downloads, nil downloads once
+	NullValues         []string        // Cell text treated as an empty cell, compared case-insensitively, e.g. "NULL", "N/A", "-"
+	DecimalSeparator   string          // Decimal mark of numbers stored as text, e.g. "," for "1.234,56"; empty means "."
+	ThousandsSeparator string          // Digit group mark removed before parsing, e.g. "." or " "; groups must have 3 digits
+	FloatEpsilon       float64         // Parsed floats with a smaller magnitude are read as 0, e.g. 1e-9 for lossy upstream exports; 0 disables
 }
 
 // ExcelImporter generic importer
@@ -32,6 +185,15 @@ type ExcelImporter[T any] struct {
 	config        *ExcelImportConfig[T]
 	dynamicField  string
 	dynamicFilter *regexp.Regexp
+	fieldColumns  map[string]string            // Struct Field -> Excel Column, reverse of FieldMappings
+	allStrings    bool                         // Every mapped field is a plain string, enabling the fast path
+	configErr     error                        // Invalid configuration, returned by every import
+	nullValues    map[string]bool              // Lowercased NullValues
+	boolTexts     map[string]bool              // Lowercased BoolValues, or defaultBoolValues
+	columnGroups  map[string]*regexp.Regexp    // Slice field -> headers it collects
+	lookupTables  map[string]map[string]string // Field -> Lookups table, set on the per-import copy from withLookups
+	cellComments  map[string]string            // Cell reference -> comment text, set on the per-import copy from withComments
+	numberCells   func(cell string) bool       // Reports cells stored as numbers, set on the per-import copy from withNumberCells
 }
 
 // NewExcelImporter creates a new importer instance
@@ -39,21 +201,198 @@ func NewExcelImporter[T any](config *ExcelImportConfig[T]) *ExcelImporter[T] {
 	if config == nil {
 		config = &ExcelImportConfig[T]{}
 	}
-	if config.StartRow == 0 {
-		config.StartRow = 2
-	}
 	if config.HeaderRow == 0 {
 		config.HeaderRow = 1
 	}
+	if config.StartRow == 0 {
+		config.StartRow = config.HeaderRow + 1
+	}
+	if config.StartColumn == 0 {
+		config.StartColumn = 1
+	}
+	if config.ProgressInterval <= 0 {
+		config.ProgressInterval = 100
+	}
 
 	importer := &ExcelImporter[T]{config: config}
+	if len(config.NullValues) > 0 {
+		importer.nullValues = make(map[string]bool, len(config.NullValues))
+		for _, value := range config.NullValues {
+			importer.nullValues[strings.ToLower(strings.TrimSpace(value))] = true
+		}
+	}
+	importer.boolTexts = defaultBoolValues
+	if config.BoolValues != nil {
+		importer.boolTexts = make(map[string]bool, len(config.BoolValues))
+		for text, value := range config.BoolValues {
+			importer.boolTexts[strings.ToLower(strings.TrimSpace(text))] = value
+		}
+	}
+	importer.configErr = importer.checkConfig()
 	importer.parseTags()
 	return importer
 }
 
+// checkConfig validates the row layout once defaults are applied
+func (importer *ExcelImporter[T]) checkConfig() error {
+	config := importer.config
+	if config.HeaderRow < 0 || config.StartRow < 0 {
+		return fmt.Errorf("invalid config: HeaderRow %d and StartRow %d must not be negative", config.HeaderRow, config.StartRow)
+	}
+	if config.StartRow <= config.HeaderRow {
+		return fmt.Errorf("invalid config: StartRow %d must be after HeaderRow %d", config.StartRow, config.HeaderRow)
+	}
+	if config.StartColumn < 0 {
+		return fmt.Errorf("invalid config: StartColumn %d must not be negative", config.StartColumn)
+	}
+	if config.KeyColumns < 0 {
+		return fmt.Errorf("invalid config: KeyColumns %d must not be negative", config.KeyColumns)
+	}
+	if config.FloatEpsilon < 0 {
+		return fmt.Errorf("invalid config: FloatEpsilon %v must not be negative", config.FloatEpsilon)
+	}
+	// BoolValues are matched case-insensitively, so texts that differ only in case must agree
+	texts := make(map[string]string, len(config.BoolValues))
+	for text, value := range config.BoolValues {
+		key := strings.ToLower(strings.TrimSpace(text))
+		if other, ok := texts[key]; ok && config.BoolValues[other] != value {
+			a, b := min(text, other), max(text, other)
+			return fmt.Errorf("invalid config: BoolValues %q and %q match the same cells but map to different values", a, b)
+		}
+		texts[key] = text
+	}
+	return nil
+}
+
+// NewExcelImporterE is NewExcelImporter that also rejects configurations which
+// would otherwise only fail, or silently do nothing, at import time
+func NewExcelImporterE[T any](config *ExcelImportConfig[T]) (*ExcelImporter[T], error) {
+	importer := NewExcelImporter(config)
+	if err := importer.checkStrict(); err != nil {
+		return nil, err
+	}
+	return importer, nil
+}
+
+// checkStrict verifies the row type and that every field name in the config exists
+func (importer *ExcelImporter[T]) checkStrict() error {
+	if importer.configErr != nil {
+		return importer.configErr
+	}
+	config := importer.config
+
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if config.Discriminator != nil {
+		if t.Kind() != reflect.Interface {
+			return fmt.Errorf("invalid config: Discriminator needs an interface row type, got %s", t)
+		}
+		return nil
+	}
+	if t.Kind() != reflect.Struct {
+		return fmt.Errorf("invalid config: row type %s is not a struct", t)
+	}
+	if len(config.FieldMappings) == 0 && importer.dynamicField == "" && len(importer.columnGroups) == 0 && len(config.CombineColumns) == 0 && len(config.Lookups) == 0 {
+		return fmt.Errorf("invalid config: %s has no excel tags or FieldMappings", t)
+	}
+
+	var errs []error
+	checkField := func(option, fieldName string) {
+		if _, ok := t.FieldByName(fieldName); !ok {
+			errs = append(errs, fmt.Errorf("invalid config: %s references unknown field %s", option, fieldName))
+		}
+	}
+	for _, fieldName := range config.FieldMappings {
+		checkField("FieldMappings", fieldName)
+	}
+	for _, option := range []struct {
+		name string
+		keys []string
+	}{
+		{"DefaultValues", slices.Collect(maps.Keys(config.DefaultValues))},
+		{"DefaultSpecs", slices.Collect(maps.Keys(config.DefaultSpecs))},
+		{"Validators", slices.Collect(maps.Keys(config.Validators))},
+		{"CustomConverters", slices.Collect(maps.Keys(config.CustomConverters))},
+		{"RowConverters", slices.Collect(maps.Keys(config.RowConverters))},
+		{"PreTransforms", slices.Collect(maps.Keys(config.PreTransforms))},
+		{"TrimSets", slices.Collect(maps.Keys(config.TrimSets))},
+		{"CombineColumns", slices.Collect(maps.Keys(config.CombineColumns))},
+		{"Lookups", slices.Collect(maps.Keys(config.Lookups))},
+		{"PercentFields", slices.Collect(maps.Keys(config.PercentFields))},
+	} {
+		sort.Strings(option.keys)
+		for _, fieldName := range option.keys {
+			checkField(option.name, fieldName)
+		}
+	}
+	if config.RowNumField != "" {
+		checkField("RowNumField", config.RowNumField)
+	}
+	for _, fieldName := range slices.Sorted(maps.Keys(config.CommentFields)) {
+		if field, ok := t.FieldByName(fieldName); !ok || field.Type.Kind() != reflect.String {
+			errs = append(errs, fmt.Errorf("invalid config: comment field %q must be a string", fieldName))
+		}
+	}
+	if config.RawRowField != "" {
+		if field, ok := t.FieldByName(config.RawRowField); !ok || field.Type != reflect.TypeOf([]string(nil)) {
+			errs = append(errs, fmt.Errorf("invalid config: raw row field %q must be a []string", config.RawRowField))
+		}
+	}
+	if config.OnTrailingCells == TrailingCellCapture {
+		if field, ok := t.FieldByName(config.TrailingField); !ok || field.Type != reflect.TypeOf([]string(nil)) {
+			errs = append(errs, fmt.Errorf("invalid config: trailing field %q must be a []string", config.TrailingField))
+		}
+	}
+	if config.RawField != "" {
+		if field, ok := t.FieldByName(config.RawField); !ok || field.Type != reflect.TypeOf(map[string]string{}) {
+			errs = append(errs, fmt.Errorf("invalid config: raw field %s must be a map[string]string", config.RawField))
+		}
+	}
+	for _, fieldName := range slices.Sorted(maps.Keys(importer.columnGroups)) {
+		if field, ok := t.FieldByName(fieldName); !ok || field.Type.Kind() != reflect.Slice {
+			errs = append(errs, fmt.Errorf("invalid config: column group field %s must be a slice", fieldName))
+		}
+	}
+	if config.KeyColumns > 0 && importer.dynamicField == "" {
+		errs = append(errs, fmt.Errorf("invalid config: KeyColumns needs a dynamic field for the columns right of the key"))
+	}
+	if config.DynamicKeyColumn != "" || config.DynamicValueColumn != "" {
+		switch {
+		case config.DynamicKeyColumn == "" || config.DynamicValueColumn == "":
+			errs = append(errs, fmt.Errorf("invalid config: DynamicKeyColumn and DynamicValueColumn must be set together"))
+		case importer.dynamicField == "":
+			errs = append(errs, fmt.Errorf("invalid config: DynamicKeyColumn needs a dynamic field"))
+		case config.KeyColumns > 0:
+			errs = append(errs, fmt.Errorf("invalid config: DynamicKeyColumn and KeyColumns cannot be combined"))
+		}
+	}
+	if importer.dynamicField != "" {
+		field, _ := t.FieldByName(importer.dynamicField)
+		if !isDynamicFieldType(field.Type) {
+			errs = append(errs, fmt.Errorf("invalid config: dynamic field %s must be a map with string keys or a slice of key/value structs, got %s", field.Name, field.Type))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// isDynamicFieldType reports whether fillDynamicField can fill a field of type t
+func isDynamicFieldType(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Map:
+		return t.Key().Kind() == reflect.String
+	case reflect.Slice:
+		elem := t.Elem()
+		return elem.Kind() == reflect.Struct && elem.NumField() >= 2 &&
+			elem.Field(0).IsExported() && elem.Field(1).IsExported() &&
+			elem.Field(0).Type.Kind() == reflect.String
+	}
+	return false
+}
+
 func (importer *ExcelImporter[T]) parseTags() {
-	var zero T
-	t := reflect.TypeOf(zero)
+	t := reflect.TypeOf((*T)(nil)).Elem()
 	if t.Kind() == reflect.Ptr {
 		t = t.Elem()
 	}
@@ -64,6 +403,9 @@ func (importer *ExcelImporter[T]) parseTags() {
 	if importer.config.FieldMappings == nil {
 		importer.config.FieldMappings = make(map[string]string)
 	}
+	// Tagged optional columns are appended, which must not write into spare
+	// capacity of the caller's slice
+	importer.config.OptionalColumns = slices.Clone(importer.config.OptionalColumns)
 
 	for i := 0; i < t.NumField(); i++ {
 		field := t.Field(i)
@@ -75,6 +417,34 @@ func (importer *ExcelImporter[T]) parseTags() {
 		parts := strings.Split(tag, ",")
 		head := strings.TrimSpace(parts[0])
 
+		if head == "raw" {
+			if importer.config.RawField == "" {
+				importer.config.RawField = field.Name
+			}
+			continue
+		}
+
+		if head == "trailing" {
+			if importer.config.TrailingField == "" {
+				importer.config.TrailingField = field.Name
+			}
+			continue
+		}
+
+		if head == "rownum" {
+			if importer.config.RowNumField == "" {
+				importer.config.RowNumField = field.Name
+			}
+			continue
+		}
+
+		if head == "rawrow" {
+			if importer.config.RawRowField == "" {
+				importer.config.RawRowField = field.Name
+			}
+			continue
+		}
+
 		if head == "*" || head == "extra" {
 			importer.dynamicField = field.Name
 			for _, part := range parts[1:] {
@@ -89,126 +459,476 @@ func (importer *ExcelImporter[T]) parseTags() {
 			continue
 		}
 
+		if pattern, ok := columnGroupPattern(parts[1:]); ok {
+			importer.addColumnGroup(field.Name, pattern)
+			continue
+		}
+
+		if slices.ContainsFunc(parts[1:], func(part string) bool { return strings.TrimSpace(part) == "comment" }) {
+			if importer.config.CommentFields == nil {
+				importer.config.CommentFields = make(map[string]string)
+			}
+			importer.config.CommentFields[field.Name] = head
+			continue
+		}
+
 		importer.config.FieldMappings[head] = field.Name
+		for _, part := range parts[1:] {
+			part = strings.TrimSpace(part)
+			if set, ok := strings.CutPrefix(part, "trimset:"); ok {
+				if importer.config.TrimSets == nil {
+					importer.config.TrimSets = make(map[string]string)
+				}
+				importer.config.TrimSets[field.Name] = set
+				continue
+			}
+			switch part {
+			case "optional":
+				if !slices.Contains(importer.config.OptionalColumns, head) {
+					importer.config.OptionalColumns = append(importer.config.OptionalColumns, head)
+				}
+			case "percent100":
+				if importer.config.PercentFields == nil {
+					importer.config.PercentFields = make(map[string]bool)
+				}
+				importer.config.PercentFields[field.Name] = true
+			}
+		}
+	}
+
+	for _, fieldName := range slices.Sorted(maps.Keys(importer.config.ColumnGroups)) {
+		importer.addColumnGroup(fieldName, importer.config.ColumnGroups[fieldName])
+	}
+
+	importer.fieldColumns = make(map[string]string, len(importer.config.FieldMappings))
+	importer.allStrings = len(importer.config.FieldMappings) > 0
+	stringType := reflect.TypeOf("")
+	for excelCol, fieldName := range importer.config.FieldMappings {
+		importer.fieldColumns[fieldName] = excelCol
+		if field, ok := t.FieldByName(fieldName); !ok || field.Type != stringType {
+			importer.allStrings = false
+		}
+	}
+	// Combined and looked up fields bypass FieldMappings but share the fast path
+	for _, fieldName := range slices.Concat(slices.Collect(maps.Keys(importer.config.CombineColumns)), slices.Collect(maps.Keys(importer.config.Lookups))) {
+		if field, ok := t.FieldByName(fieldName); !ok || field.Type != stringType {
+			importer.allStrings = false
+		}
 	}
 }
 
 func (importer *ExcelImporter[T]) Import(url string) ([]T, error) {
-	body, _, err := downloadFromUrl(url)
+	body, _, err := importer.download(context.Background(), url)
 	if err != nil {
-		return nil, fmt.Errorf("download failed: %v", err)
+		return nil, fmt.Errorf("download failed: %w", err)
 	}
-	f, err := excelize.OpenReader(body)
+	defer body.Close()
+	wb, err := importer.openWorkbookReader(body)
+	if err != nil {
+		return nil, fmt.Errorf("open excel failed: %w", err)
+	}
+	defer wb.Close()
+	return wholeImport(importer.importWorkbook(wb))
+}
+
+// ImportReader imports from an already opened stream, e.g. an uploaded file
+func (importer *ExcelImporter[T]) ImportReader(r io.Reader) ([]T, error) {
+	wb, err := importer.openWorkbookReader(r)
 	if err != nil {
-		return nil, fmt.Errorf("open excel failed: %v", err)
+		return nil, fmt.Errorf("open excel failed: %w", err)
 	}
-	defer f.Close()
-	return importer.importFromFile(f)
+	defer wb.Close()
+	return wholeImport(importer.importWorkbook(wb))
 }
 
 func (importer *ExcelImporter[T]) ImportLocal(filePath string) ([]T, error) {
-	f, err := excelize.OpenFile(filePath)
+	wb, err := importer.openWorkbookLocal(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("open excel failed: %w", err)
+	}
+	defer wb.Close()
+	return wholeImport(importer.importWorkbook(wb))
+}
+
+// ImportPartial is Import that keeps what it has when a row fails: it stops at
+// the first row error and returns the rows parsed before it, the failing sheet
+// row number and the error, so a caller can process the prefix and resume from
+// that row. The row number is 0 for errors not tied to a row.
+func (importer *ExcelImporter[T]) ImportPartial(url string) ([]T, int, error) {
+	body, _, err := importer.download(context.Background(), url)
+	if err != nil {
+		return nil, 0, fmt.Errorf("download failed: %w", err)
+	}
+	defer body.Close()
+	return importer.ImportPartialReader(body)
+}
+
+// ImportPartialReader is ImportPartial for an already opened stream
+func (importer *ExcelImporter[T]) ImportPartialReader(r io.Reader) ([]T, int, error) {
+	wb, err := importer.openWorkbookReader(r)
 	if err != nil {
-		return nil, fmt.Errorf("open excel failed: %v", err)
+		return nil, 0, fmt.Errorf("open excel failed: %w", err)
 	}
-	defer f.Close()
-	return importer.importFromFile(f)
+	defer wb.Close()
+	return partialImport(importer.importWorkbook(wb))
+}
+
+// ImportPartialLocal is ImportPartial for a local file
+func (importer *ExcelImporter[T]) ImportPartialLocal(filePath string) ([]T, int, error) {
+	wb, err := importer.openWorkbookLocal(filePath)
+	if err != nil {
+		return nil, 0, fmt.Errorf("open excel failed: %w", err)
+	}
+	defer wb.Close()
+	return partialImport(importer.importWorkbook(wb))
+}
+
+// partialImport keeps the rows parsed before a failing row and reports its sheet row
+func partialImport[T any](data []T, err error) ([]T, int, error) {
+	var rowErr RowError
+	if errors.As(err, &rowErr) {
+		return data, rowErr.RowIndex, err
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	return data, 0, nil
+}
+
+// wholeImport drops the rows parsed before a failing row, so an import either
+// returns every row or none
+func wholeImport[T any](data []T, err error) ([]T, error) {
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
 }
 
 func (importer *ExcelImporter[T]) ImportStream(url string) <-chan ImportResult[T] {
+	return importer.ImportStreamContext(context.Background(), url)
+}
+
+// ImportStreamContext is ImportStream with cancellation. Cancel ctx when leaving the
+// range loop early: the producer then stops and releases the file instead of
+// blocking on the channel forever.
+func (importer *ExcelImporter[T]) ImportStreamContext(ctx context.Context, url string) <-chan ImportResult[T] {
 	ch := make(chan ImportResult[T])
 
 	go func() {
 		defer close(ch)
 
-		body, _, err := downloadFromUrl(url)
+		body, _, err := importer.download(ctx, url)
 		if err != nil {
-			ch <- ImportResult[T]{Error: fmt.Errorf("download failed: %v", err)}
+			sendResult(ctx, ch, ImportResult[T]{Error: fmt.Errorf("download failed: %w", err)})
 			return
 		}
-		
-		f, err := excelize.OpenReader(body)
+		defer body.Close()
+
+		wb, err := importer.openWorkbookReader(body)
 		if err != nil {
-			ch <- ImportResult[T]{Error: fmt.Errorf("open excel failed: %v", err)}
+			sendResult(ctx, ch, ImportResult[T]{Error: fmt.Errorf("open excel failed: %w", err)})
 			return
 		}
-		defer f.Close()
+		defer wb.Close()
 
-		importer.streamRows(f, ch)
+		importer.streamRows(ctx, wb, ch)
 	}()
 
 	return ch
 }
 
 func (importer *ExcelImporter[T]) ImportStreamLocal(filePath string) <-chan ImportResult[T] {
+	return importer.ImportStreamLocalContext(context.Background(), filePath)
+}
+
+// ImportStreamLocalContext is the local file variant of ImportStreamContext
+func (importer *ExcelImporter[T]) ImportStreamLocalContext(ctx context.Context, filePath string) <-chan ImportResult[T] {
 	ch := make(chan ImportResult[T])
 
 	go func() {
 		defer close(ch)
 
-		f, err := excelize.OpenFile(filePath)
+		wb, err := importer.openWorkbookLocal(filePath)
 		if err != nil {
-			ch <- ImportResult[T]{Error: fmt.Errorf("open excel failed: %v", err)}
+			sendResult(ctx, ch, ImportResult[T]{Error: fmt.Errorf("open excel failed: %w", err)})
 			return
 		}
-		defer f.Close()
+		defer wb.Close()
 
-		importer.streamRows(f, ch)
+		importer.streamRows(ctx, wb, ch)
 	}()
 
 	return ch
 }
 
-func (importer *ExcelImporter[T]) streamRows(f *excelize.File, ch chan<- ImportResult[T]) {
-	sheetName := importer.config.SheetName
-	if sheetName == "" {
-		if f.SheetCount < 1 {
-			ch <- ImportResult[T]{Error: fmt.Errorf("excel file has no sheets")}
+// ImportFromFile imports from a workbook the caller has already opened. The file
+// is not closed, so one handle can be shared by importers reading different sheets.
+func (importer *ExcelImporter[T]) ImportFromFile(f *excelize.File) ([]T, error) {
+	return wholeImport(importer.importFromFile(f))
+}
+
+// ImportStreamFromFile is the streaming variant of ImportFromFile. The file must
+// stay open until the channel is closed.
+func (importer *ExcelImporter[T]) ImportStreamFromFile(f *excelize.File) <-chan ImportResult[T] {
+	return importer.ImportStreamFromFileContext(context.Background(), f)
+}
+
+// ImportStreamFromFileContext is ImportStreamFromFile with cancellation, see ImportStreamContext
+func (importer *ExcelImporter[T]) ImportStreamFromFileContext(ctx context.Context, f *excelize.File) <-chan ImportResult[T] {
+	ch := make(chan ImportResult[T])
+
+	go func() {
+		defer close(ch)
+		importer.streamRows(ctx, &workbook{file: f}, ch)
+	}()
+
+	return ch
+}
+
+// Validate downloads the file and runs the full parse and validation pipeline
+// without collecting the parsed rows, returning only the problems found.
+func (importer *ExcelImporter[T]) Validate(url string) []RowError {
+	body, _, err := importer.download(context.Background(), url)
+	if err != nil {
+		return []RowError{{Err: fmt.Errorf("download failed: %w", err)}}
+	}
+	defer body.Close()
+	return importer.ValidateReader(body)
+}
+
+// ValidateLocal is the local file variant of Validate
+func (importer *ExcelImporter[T]) ValidateLocal(filePath string) []RowError {
+	wb, err := importer.openWorkbookLocal(filePath)
+	if err != nil {
+		return []RowError{{Err: fmt.Errorf("open excel failed: %w", err)}}
+	}
+	defer wb.Close()
+	return importer.validateWorkbook(wb)
+}
+
+// ValidateReader is the io.Reader variant of Validate
+func (importer *ExcelImporter[T]) ValidateReader(r io.Reader) []RowError {
+	wb, err := importer.openWorkbookReader(r)
+	if err != nil {
+		return []RowError{{Err: fmt.Errorf("open excel failed: %w", err)}}
+	}
+	defer wb.Close()
+	return importer.validateWorkbook(wb)
+}
+
+func (importer *ExcelImporter[T]) validateWorkbook(wb *workbook) []RowError {
+	ch := make(chan ImportResult[T])
+	go func() {
+		defer close(ch)
+		importer.streamRows(context.Background(), wb, ch)
+	}()
+
+	var errs []RowError
+	for res := range ch {
+		if res.Error != nil {
+			errs = append(errs, RowError{RowIndex: res.RowIndex, Err: res.Error})
+		}
+	}
+	return errs
+}
+
+func (importer *ExcelImporter[T]) streamRows(ctx context.Context, wb *workbook, ch chan<- ImportResult[T]) {
+	scanner, err := importer.newRowScanner(wb)
+	if err != nil {
+		sendResult(ctx, ch, ImportResult[T]{Error: err})
+		return
+	}
+	defer scanner.rows.Close()
+
+	for {
+		res, ok := scanner.next()
+		if !ok {
+			return
+		}
+		if !sendResult(ctx, ch, res) {
 			return
 		}
-		sheetName = f.GetSheetName(0)
 	}
+}
+
+// sendResult delivers res unless ctx is done first, reporting whether it was sent.
+// A cancelled ctx is checked before the select, which picks at random when the
+// receiver is also ready, so at most one result follows a cancellation.
+func sendResult[T any](ctx context.Context, ch chan<- ImportResult[T], res ImportResult[T]) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	select {
+	case ch <- res:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// newRowScanner opens the sheet's rows and loads Lookups for a scan
+func (importer *ExcelImporter[T]) newRowScanner(wb *workbook) (*rowScanner[T], error) {
+	rows, err := importer.openRowIterator(wb)
+	if err != nil {
+		return nil, err
+	}
+	scanImporter, err := importer.withLookups(wb.file)
+	if err == nil && len(importer.config.CommentFields) > 0 {
+		var sheetName string
+		if wb.file != nil {
+			sheetName, _ = importer.resolveSheet(wb.file)
+		}
+		scanImporter, err = scanImporter.withComments(wb.file, sheetName)
+	}
+	if err == nil && wb.file != nil {
+		sheetName, _ := importer.resolveSheet(wb.file)
+		scanImporter = scanImporter.withNumberCells(wb.file, sheetName)
+	}
+	if err != nil {
+		_ = rows.Close()
+		return nil, err
+	}
+	return &rowScanner[T]{importer: scanImporter, rows: rows}, nil
+}
 
+func (importer *ExcelImporter[T]) openRowIterator(wb *workbook) (rowIterator, error) {
+	if importer.configErr != nil {
+		return nil, importer.configErr
+	}
+	if wb.file == nil {
+		if wb.xlsSheetErr != nil {
+			return nil, wb.xlsSheetErr
+		}
+		return importer.offsetRows(&sliceRows{rows: wb.xlsRows}), nil
+	}
+
+	f := wb.file
+	sheetName, err := importer.resolveSheet(f)
+	if err != nil {
+		return nil, err
+	}
+
+	iter, err := importer.sheetRows(f, sheetName)
+	if err != nil {
+		return nil, err
+	}
+	return importer.offsetRows(iter), nil
+}
+
+// sheetRows opens a sheet's rows with cell fills applied
+func (importer *ExcelImporter[T]) sheetRows(f *excelize.File, sheetName string) (rowIterator, error) {
 	rows, err := f.Rows(sheetName)
 	if err != nil {
-		ch <- ImportResult[T]{Error: fmt.Errorf("read sheet failed: %v", err)}
-		return
+		return nil, fmt.Errorf("read sheet failed: %v", err)
 	}
-	defer rows.Close()
+	var iter rowIterator = excelizeRows{Rows: rows, opts: importer.readOptions()}
+	fills, err := importer.readFills(f, sheetName)
+	if err != nil {
+		_ = iter.Close()
+		return nil, err
+	}
+	if fills != nil {
+		iter = &filledRows{rows: iter, fills: fills}
+	}
+	return iter, nil
+}
 
-	var columnIndexMap map[string]int
-	rowIndex := 0
+// rowScanner walks a row iterator and produces one ImportResult per data row.
+// Results that end the scan (bad header, read failures) set done.
+type rowScanner[T any] struct {
+	importer       *ExcelImporter[T]
+	rows           rowIterator
+	columnIndexMap map[string]int
+	projection     []int
+	headerWidth    int
+	rowIndex       int
+	comments       int
+	stats          importStats
+	started        bool
+	done           bool
+	finished       bool
+}
 
-	for rows.Next() {
-		rowIndex++
-		
-		// Skip rows
-		if importer.config.SkipRows[rowIndex] {
-			continue
+func (s *rowScanner[T]) next() (ImportResult[T], bool) {
+	if !s.started {
+		s.started = true
+		s.stats = s.importer.newImportStats()
+	}
+
+	res, ok := s.scan()
+	if limit := s.importer.config.MaxErrors; limit > 0 && ok && res.Error != nil && !s.done && s.stats.errors >= limit {
+		// Stop instead of reporting one more row error
+		s.done = true
+		res = ImportResult[T]{Error: fmt.Errorf("%w (>%d)", ErrTooManyErrors, limit)}
+	}
+	switch {
+	case ok && res.RowIndex > 0 && !s.done:
+		s.stats.processed++
+		if res.Error != nil {
+			s.stats.errors++
+			s.importer.emit(Event{Kind: EventRowError, RowIndex: res.RowIndex, Err: res.Error}, s.stats)
 		}
+		s.importer.reportProgress(s.stats, false)
+	case ok && s.done && !s.finished:
+		// Fatal results end the scan, so report completion now in case the caller stops here
+		s.finished = true
+		s.stats.err = res.Error
+		s.importer.reportProgress(s.stats, true)
+	case !ok && !s.finished:
+		s.finished = true
+		s.importer.reportProgress(s.stats, true)
+	}
+	return res, ok
+}
+
+func (s *rowScanner[T]) scan() (ImportResult[T], bool) {
+	importer := s.importer
+
+	for !s.done && s.rows.Next() {
+		s.rowIndex++
+		rowIndex := s.rowIndex
 
 		// Read row columns
-		row, err := rows.Columns()
+		row, err := s.rows.Columns()
 		if err != nil {
-			ch <- ImportResult[T]{RowIndex: rowIndex, Error: fmt.Errorf("read row %d failed: %v", rowIndex, err)}
-			return
+			s.done = true
+			return ImportResult[T]{RowIndex: rowIndex, Error: fmt.Errorf("read row %d failed: %v", rowIndex, err)}, true
+		}
+
+		row = importer.sanitizeColumns(row, s.projection)
+
+		// Comment rows do not count toward MaxRows
+		if importer.isCommentRow(row, rowIndex) {
+			s.comments++
+			continue
+		}
+
+		if importer.config.MaxRows > 0 && rowIndex-s.comments > importer.config.MaxRows {
+			s.done = true
+			return ImportResult[T]{RowIndex: rowIndex, Error: fmt.Errorf("sheet exceeds max rows %d", importer.config.MaxRows)}, true
+		}
+
+		// Skip rows
+		if importer.config.SkipRows[rowIndex] {
+			continue
 		}
 
 		// Handle Header
 		if rowIndex == importer.config.HeaderRow {
-			columnIndexMap = importer.buildColumnIndexMap(row)
-			
-			// Validate headers
-			missingColumns := make([]string, 0)
-			for excelCol := range importer.config.FieldMappings {
-				if _, exists := columnIndexMap[excelCol]; !exists {
-					missingColumns = append(missingColumns, excelCol)
-				}
+			if err := importer.checkRowLimits(row); err != nil {
+				s.done = true
+				return ImportResult[T]{RowIndex: rowIndex, Error: err}, true
 			}
-			if len(missingColumns) > 0 {
-				ch <- ImportResult[T]{RowIndex: rowIndex, Error: fmt.Errorf("missing columns: %s", strings.Join(missingColumns, ", "))}
-				return
+			s.columnIndexMap = importer.buildColumnIndexMap(row)
+			s.projection = importer.projectColumns(s.columnIndexMap)
+			s.headerWidth = len(row)
+
+			// Validate headers
+			if err := importer.checkMissingColumns(s.columnIndexMap); err != nil {
+				s.done = true
+				return ImportResult[T]{RowIndex: rowIndex, Error: err}, true
 			}
+			importer.emit(Event{Kind: EventHeaderResolved, RowIndex: rowIndex, Columns: row}, s.stats)
 			continue
 		}
 
@@ -221,115 +941,601 @@ func (importer *ExcelImporter[T]) streamRows(f *excelize.File, ch chan<- ImportR
 			continue
 		}
 
-		instance, err := importer.parseRow(row, columnIndexMap)
+		instance, err := importer.parseRow(padRow(row, s.headerWidth), rowIndex, s.columnIndexMap)
 		if err != nil {
-			ch <- ImportResult[T]{RowIndex: rowIndex, Error: err}
-			continue // Continue processing other rows
+			// Row errors do not stop the scan
+			return ImportResult[T]{RowIndex: rowIndex, Error: err}, true
 		}
+		if !importer.accepts(&instance) {
+			continue
+		}
+
+		return ImportResult[T]{RowIndex: rowIndex, Data: instance}, true
+	}
+	return ImportResult[T]{}, false
+}
+
+// SheetNames downloads the file and lists its sheets without importing any rows
+func (importer *ExcelImporter[T]) SheetNames(url string) ([]string, error) {
+	body, _, err := importer.download(context.Background(), url)
+	if err != nil {
+		return nil, fmt.Errorf("download failed: %w", err)
+	}
+	defer body.Close()
+	return importer.SheetNamesReader(body)
+}
+
+// SheetNamesLocal is the local file variant of SheetNames
+func (importer *ExcelImporter[T]) SheetNamesLocal(filePath string) ([]string, error) {
+	wb, err := importer.openWorkbookLocal(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("open excel failed: %w", err)
+	}
+	defer wb.Close()
+	return wb.SheetNames(), nil
+}
+
+// SheetNamesReader is the io.Reader variant of SheetNames
+func (importer *ExcelImporter[T]) SheetNamesReader(r io.Reader) ([]string, error) {
+	wb, err := importer.openWorkbookReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("open excel failed: %w", err)
+	}
+	defer wb.Close()
+	return wb.SheetNames(), nil
+}
+
+// ImportAllSheets downloads the workbook and imports every sheet. Sheets listed in
+// overrides are imported with their own config; all others use the base config.
+func (importer *ExcelImporter[T]) ImportAllSheets(url string, overrides map[string]*ExcelImportConfig[T]) (map[string][]T, error) {
+	body, _, err := importer.download(context.Background(), url)
+	if err != nil {
+		return nil, fmt.Errorf("download failed: %w", err)
+	}
+	defer body.Close()
+	wb, err := importer.openWorkbookReader(body)
+	if err != nil {
+		return nil, fmt.Errorf("open excel failed: %w", err)
+	}
+	defer wb.Close()
+	return importer.importAllSheets(wb, overrides)
+}
+
+// ImportAllSheetsLocal is the local file variant of ImportAllSheets
+func (importer *ExcelImporter[T]) ImportAllSheetsLocal(filePath string, overrides map[string]*ExcelImportConfig[T]) (map[string][]T, error) {
+	wb, err := importer.openWorkbookLocal(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("open excel failed: %w", err)
+	}
+	defer wb.Close()
+	return importer.importAllSheets(wb, overrides)
+}
+
+func (importer *ExcelImporter[T]) importAllSheets(wb *workbook, overrides map[string]*ExcelImportConfig[T]) (map[string][]T, error) {
+	result := make(map[string][]T)
+	for _, sheetName := range wb.SheetNames() {
+		sheetImporter := importer
+		if override, ok := overrides[sheetName]; ok && override != nil {
+			sheetImporter = NewExcelImporter(override)
+		}
+
+		data, err := sheetImporter.importWorkbookSheet(wb, sheetName)
+		if err != nil {
+			return nil, fmt.Errorf("sheet %s: %v", sheetName, err)
+		}
+		result[sheetName] = data
+	}
+	return result, nil
+}
+
+func (importer *ExcelImporter[T]) importFromFile(f *excelize.File) ([]T, error) {
+	sheetName, err := importer.resolveSheet(f)
+	if err != nil {
+		return nil, err
+	}
+	return importer.importSheet(f, sheetName)
+}
+
+// resolveSheet returns the configured sheet, or the first one when SheetName is empty
+func (importer *ExcelImporter[T]) resolveSheet(f *excelize.File) (string, error) {
+	sheets := f.GetSheetList()
+	if len(sheets) == 0 {
+		return "", ErrNoSheets
+	}
+	sheetName := importer.config.SheetName
+	if sheetName == "" {
+		return sheets[0], nil
+	}
+	if !slices.Contains(sheets, sheetName) {
+		return "", &SheetNotFoundError{Sheet: sheetName, Available: sheets}
+	}
+	return sheetName, nil
+}
+
+func (importer *ExcelImporter[T]) importSheet(f *excelize.File, sheetName string) ([]T, error) {
+	importer, err := importer.withLookups(f)
+	if err != nil {
+		return nil, err
+	}
+	if importer, err = importer.withComments(f, sheetName); err != nil {
+		return nil, err
+	}
+	importer = importer.withNumberCells(f, sheetName)
+	rows, err := importer.readRows(f, sheetName)
+	if err != nil {
+		return nil, err
+	}
+	return importer.importRows(rows)
+}
+
+// readRows reads a sheet for importRows, enforcing the limits as rows come in
+// so an oversized sheet is never held whole. Reading stops at the first row
+// past MaxRows, or the first header or data row breaking MaxColumns or
+// MaxCellLength, which importRows then rejects as it would the whole sheet.
+// Like GetRows, trailing empty rows are dropped.
+func (importer *ExcelImporter[T]) readRows(f *excelize.File, sheetName string) ([][]string, error) {
+	iter, err := importer.sheetRows(f, sheetName)
+	if err != nil {
+		return nil, err
+	}
+	defer iter.Close()
+
+	var rows [][]string
+	comments := 0
+	for rowIndex := 1; iter.Next(); rowIndex++ {
+		row, err := iter.Columns()
+		if err != nil {
+			return nil, fmt.Errorf("read row %d failed: %v", rowIndex, err)
+		}
+		if len(row) == 0 {
+			continue
+		}
+		for len(rows) < rowIndex-1 {
+			rows = append(rows, nil)
+		}
+		rows = append(rows, row)
+
+		checked := importer.sanitizeRow(importer.offsetRow(row))
+		if importer.isCommentRow(checked, rowIndex) {
+			comments++
+			continue
+		}
+		if importer.config.MaxRows > 0 && rowIndex-comments > importer.config.MaxRows {
+			break
+		}
+		if importer.limitsRow(checked, rowIndex) && importer.checkRowLimits(checked) != nil {
+			break
+		}
+	}
+	return rows, nil
+}
+
+// limitsRow reports whether importRows checks the row against MaxColumns and
+// MaxCellLength: the header and every data row it parses
+func (importer *ExcelImporter[T]) limitsRow(row []string, rowIndex int) bool {
+	if rowIndex == importer.config.HeaderRow {
+		return true
+	}
+	return rowIndex >= importer.config.StartRow && !importer.config.SkipRows[rowIndex] && !importer.isEmptyRow(row)
+}
+
+// importRows parses a sheet's rows. On a row error the rows parsed before it are
+// returned along with a RowError; public imports drop them with wholeImport.
+func (importer *ExcelImporter[T]) importRows(rows [][]string) ([]T, error) {
+	stats := importer.newImportStats()
+	if importer.configErr != nil {
+		return nil, importer.fail(stats, importer.configErr)
+	}
+	if len(rows) < importer.config.HeaderRow {
+		return nil, importer.fail(stats, fmt.Errorf("insufficient rows"))
+	}
+	if importer.config.MaxRows > 0 && len(rows)-importer.countCommentRows(rows) > importer.config.MaxRows {
+		return nil, importer.fail(stats, fmt.Errorf("sheet exceeds max rows %d", importer.config.MaxRows))
+	}
+
+	headerRow := importer.sanitizeRow(importer.offsetRow(rows[importer.config.HeaderRow-1]))
+	if err := importer.checkRowLimits(headerRow); err != nil {
+		return nil, importer.fail(stats, fmt.Errorf("header row error: %v", err))
+	}
+	columnIndexMap := importer.buildColumnIndexMap(headerRow)
+	projection := importer.projectColumns(columnIndexMap)
+
+	if err := importer.checkMissingColumns(columnIndexMap); err != nil {
+		return nil, importer.fail(stats, err)
+	}
+	importer.emit(Event{Kind: EventHeaderResolved, RowIndex: importer.config.HeaderRow, Columns: headerRow}, stats)
+
+	var result []T
+
+	for i := importer.config.StartRow - 1; i < len(rows); i++ {
+		if importer.config.SkipRows[i+1] {
+			continue
+		}
+
+		row := importer.sanitizeColumns(importer.offsetRow(rows[i]), projection)
+		if importer.isEmptyRow(row) || importer.isCommentRow(row, i+1) {
+			continue
+		}
+
+		instance, err := importer.parseRow(padRow(row, len(headerRow)), i+1, columnIndexMap)
+		if err != nil {
+			stats.processed++
+			stats.errors++
+			importer.emit(Event{Kind: EventRowError, RowIndex: i + 1, Err: err}, stats)
+			return result, importer.fail(stats, RowError{RowIndex: i + 1, Err: err})
+		}
+		if !importer.accepts(&instance) {
+			continue
+		}
+
+		result = append(result, instance)
+		stats.processed++
+		importer.reportProgress(stats, false)
+	}
+
+	importer.reportProgress(stats, true)
+	return result, nil
+}
+
+func (importer *ExcelImporter[T]) parseRow(row []string, rowIndex int, columnIndexMap map[string]int) (T, error) {
+	var instance T
+	if err := importer.checkRowLimits(row); err != nil {
+		return instance, err
+	}
+	if importer.config.OnTrailingCells == TrailingCellError {
+		if trailing, width := trailingCells(row, columnIndexMap); len(trailing) > 0 {
+			return instance, fmt.Errorf("row has %d cells, header has %d columns", width+len(trailing), width)
+		}
+	}
+	if importer.config.Discriminator != nil {
+		return importer.parseVariant(row, rowIndex, columnIndexMap)
+	}
+
+	val := reflect.ValueOf(&instance)
+	if val.Kind() == reflect.Ptr {
+		val = val.Elem()
+	}
+	if val.Kind() == reflect.Ptr {
+		if val.IsNil() {
+			val.Set(reflect.New(val.Type().Elem()))
+		}
+		val = val.Elem()
+	}
+
+	if importer.config.RowNumField != "" {
+		field := val.FieldByName(importer.config.RowNumField)
+		if !field.IsValid() || !field.CanSet() {
+			return instance, fmt.Errorf("row number field %s not found", importer.config.RowNumField)
+		}
+		if err := importer.setFieldValue(field, rowIndex); err != nil {
+			return instance, fmt.Errorf("row number field %s: %v", importer.config.RowNumField, err)
+		}
+	}
+
+	if importer.config.RawRowField != "" {
+		field := val.FieldByName(importer.config.RawRowField)
+		if !field.IsValid() || field.Type() != reflect.TypeOf([]string(nil)) {
+			return instance, fmt.Errorf("raw row field %s must be a []string", importer.config.RawRowField)
+		}
+		field.Set(reflect.ValueOf(slices.Clone(row)))
+	}
+
+	if err := importer.fillComments(val, rowIndex, columnIndexMap); err != nil {
+		return instance, err
+	}
+
+	if err := importer.fillStruct(val, row, rowIndex, columnIndexMap, &instance); err != nil {
+		return instance, err
+	}
+
+	if err := importer.validateData(val); err != nil {
+		return instance, err
+	}
+	return instance, nil
+}
+
+// accepts reports whether a parsed row passes FilterPredicate
+func (importer *ExcelImporter[T]) accepts(instance *T) bool {
+	return importer.config.FilterPredicate == nil || importer.config.FilterPredicate(instance)
+}
+
+func (importer *ExcelImporter[T]) buildColumnIndexMap(headerRow []string) map[string]int {
+	indexMap := make(map[string]int)
+	for idx, cellValue := range headerRow {
+		cleanName := strings.Trim(strings.TrimSpace(cellValue), "*")
+		if alias, ok := importer.config.HeaderAliases[cleanName]; ok {
+			cleanName = alias
+		}
+		// A key column keeps its header when the same text repeats in the value grid
+		if prev, exists := indexMap[cleanName]; exists && prev < importer.config.KeyColumns {
+			continue
+		}
+		indexMap[cleanName] = idx
+	}
+	return indexMap
+}
+
+func (importer *ExcelImporter[T]) checkRowLimits(row []string) error {
+	if importer.config.MaxColumns > 0 && len(row) > importer.config.MaxColumns {
+		return fmt.Errorf("row has %d columns, exceeds max %d", len(row), importer.config.MaxColumns)
+	}
+	if importer.config.MaxCellLength > 0 {
+		for idx, cell := range row {
+			if len(cell) > importer.config.MaxCellLength {
+				return fmt.Errorf("cell in column %d exceeds max length %d", idx+importer.config.StartColumn, importer.config.MaxCellLength)
+			}
+		}
+	}
+	return nil
+}
+
+func (importer *ExcelImporter[T]) checkMissingColumns(columnIndexMap map[string]int) error {
+	if importer.config.Discriminator != nil {
+		return importer.config.Discriminator.checkColumns(columnIndexMap)
+	}
+	missingColumns := make([]string, 0)
+	for excelCol, fieldName := range importer.config.FieldMappings {
+		if _, exists := importer.mappedColumn(columnIndexMap, excelCol); !exists {
+			if importer.config.DefaultSpecs[fieldName].EmptyOnly {
+				missingColumns = append(missingColumns, excelCol)
+				continue
+			}
+			if slices.Contains(importer.config.OptionalColumns, excelCol) {
+				importer.warn(Warning{RowIndex: importer.config.HeaderRow, Column: excelCol, Message: "optional column is missing"})
+				continue
+			}
+			if importer.config.OnMissingColumn == MissingColumnError {
+				missingColumns = append(missingColumns, excelCol)
+			}
+		}
+	}
+	for _, lookup := range importer.config.Lookups {
+		if _, exists := importer.mappedColumn(columnIndexMap, lookup.Column); !exists && !slices.Contains(missingColumns, lookup.Column) &&
+			importer.config.OnMissingColumn == MissingColumnError {
+			missingColumns = append(missingColumns, lookup.Column)
+		}
+	}
+	if importer.config.DynamicKeyColumn != "" && importer.config.OnMissingColumn == MissingColumnError {
+		for _, excelCol := range []string{importer.config.DynamicKeyColumn, importer.config.DynamicValueColumn} {
+			if _, exists := columnIndexMap[excelCol]; !exists && !slices.Contains(missingColumns, excelCol) {
+				missingColumns = append(missingColumns, excelCol)
+			}
+		}
+	}
+	for _, spec := range importer.config.CombineColumns {
+		for _, excelCol := range spec.Columns {
+			if _, exists := columnIndexMap[excelCol]; exists || slices.Contains(missingColumns, excelCol) {
+				continue
+			}
+			if slices.Contains(importer.config.OptionalColumns, excelCol) {
+				importer.warn(Warning{RowIndex: importer.config.HeaderRow, Column: excelCol, Message: "optional column is missing"})
+				continue
+			}
+			if importer.config.OnMissingColumn == MissingColumnError {
+				missingColumns = append(missingColumns, excelCol)
+			}
+		}
+	}
+	if len(missingColumns) > 0 {
+		sort.Strings(missingColumns)
+		return &MissingColumnsError{Missing: missingColumns, Found: foundColumns(columnIndexMap)}
+	}
+	return nil
+}
+
+// mappedColumn looks up the column of a mapped field. With KeyColumns set, a
+// matching header right of the key columns belongs to the dynamic field instead.
+func (importer *ExcelImporter[T]) mappedColumn(columnIndexMap map[string]int, excelCol string) (int, bool) {
+	idx, exists := columnIndexMap[excelCol]
+	if exists && importer.config.KeyColumns > 0 && idx >= importer.config.KeyColumns {
+		return idx, false
+	}
+	return idx, exists
+}
+
+// trailingCells returns the cells right of the header, up to the last non-empty
+// one, along with the header width
+func trailingCells(row []string, columnIndexMap map[string]int) ([]string, int) {
+	width := 0
+	for _, idx := range columnIndexMap {
+		width = max(width, idx+1)
+	}
+	end := len(row)
+	for end > width && strings.TrimSpace(row[end-1]) == "" {
+		end--
+	}
+	if end <= width {
+		return nil, width
+	}
+	return slices.Clone(row[width:end]), width
+}
 
-		ch <- ImportResult[T]{RowIndex: rowIndex, Data: instance}
+// foundColumns lists the header names of a column index map in sheet order
+func foundColumns(columnIndexMap map[string]int) []string {
+	found := make([]string, 0, len(columnIndexMap))
+	for name := range columnIndexMap {
+		found = append(found, name)
 	}
+	sort.Slice(found, func(i, j int) bool { return columnIndexMap[found[i]] < columnIndexMap[found[j]] })
+	return found
 }
 
-func (importer *ExcelImporter[T]) importFromFile(f *excelize.File) ([]T, error) {
-	sheetName := importer.config.SheetName
-	if sheetName == "" {
-		if f.SheetCount < 1 {
-			return nil, fmt.Errorf("excel file has no sheets")
+// defaultFor returns the default for a field whose column is missing or whose cell is empty
+func (importer *ExcelImporter[T]) defaultFor(fieldName string, columnMissing bool) (any, bool) {
+	if spec, ok := importer.config.DefaultSpecs[fieldName]; ok {
+		if columnMissing && spec.EmptyOnly {
+			return nil, false
 		}
-		sheetName = f.GetSheetName(0)
+		return spec.Value, true
 	}
+	value, ok := importer.config.DefaultValues[fieldName]
+	return value, ok
+}
 
-	rows, err := f.GetRows(sheetName)
-	if err != nil {
-		return nil, fmt.Errorf("read sheet failed: %v", err)
+func (importer *ExcelImporter[T]) warn(w Warning) {
+	if importer.config.OnWarning != nil {
+		importer.config.OnWarning(w)
 	}
+}
 
-	if len(rows) < importer.config.HeaderRow {
-		return nil, fmt.Errorf("insufficient rows")
+func (importer *ExcelImporter[T]) sanitizeRow(row []string) []string {
+	if !importer.config.SanitizeCells {
+		return row
 	}
+	for idx, cell := range row {
+		row[idx] = sanitizeCell(cell)
+	}
+	return row
+}
 
-	headerRow := rows[importer.config.HeaderRow-1]
-	columnIndexMap := importer.buildColumnIndexMap(headerRow)
-
-	missingColumns := make([]string, 0)
+// projectColumns returns the indexes of the mapped columns when nothing else reads
+// the row, so per-cell work can skip the rest; nil means every column is needed.
+// RowConverters, trailing cells, CommentPrefix and RawRowField read unmapped cells.
+func (importer *ExcelImporter[T]) projectColumns(columnIndexMap map[string]int) []int {
+	if !importer.config.SanitizeCells || importer.dynamicField != "" || len(importer.columnGroups) > 0 ||
+		importer.config.RowHook != nil || importer.config.Discriminator != nil || len(importer.config.RowConverters) > 0 ||
+		importer.config.OnTrailingCells != TrailingCellIgnore || importer.config.CommentPrefix != "" || importer.config.RawRowField != "" {
+		return nil
+	}
+	projection := make([]int, 0, len(importer.config.FieldMappings))
 	for excelCol := range importer.config.FieldMappings {
-		if _, exists := columnIndexMap[excelCol]; !exists {
-			missingColumns = append(missingColumns, excelCol)
+		if idx, exists := columnIndexMap[excelCol]; exists {
+			projection = append(projection, idx)
 		}
 	}
-	if len(missingColumns) > 0 {
-		return nil, fmt.Errorf("missing columns: %s", strings.Join(missingColumns, ", "))
-	}
-
-	var result []T
-
-	for i := importer.config.StartRow - 1; i < len(rows); i++ {
-		if importer.config.SkipRows[i+1] {
-			continue
+	for _, spec := range importer.config.CombineColumns {
+		for _, excelCol := range spec.Columns {
+			if idx, exists := columnIndexMap[excelCol]; exists {
+				projection = append(projection, idx)
+			}
 		}
-
-		row := rows[i]
-		if importer.isEmptyRow(row) {
-			continue
+	}
+	for _, lookup := range importer.config.Lookups {
+		if idx, exists := columnIndexMap[lookup.Column]; exists {
+			projection = append(projection, idx)
 		}
+	}
+	return projection
+}
 
-		instance, err := importer.parseRow(row, columnIndexMap)
-		if err != nil {
-			return nil, fmt.Errorf("row %d error: %v", i+1, err)
+// sanitizeColumns sanitizes only the projected cells of a row, or all of them for a nil projection
+func (importer *ExcelImporter[T]) sanitizeColumns(row []string, projection []int) []string {
+	if projection == nil {
+		return importer.sanitizeRow(row)
+	}
+	for _, idx := range projection {
+		if idx < len(row) {
+			row[idx] = sanitizeCell(row[idx])
 		}
-
-		result = append(result, instance)
 	}
+	return row
+}
 
-	return result, nil
+// sanitizeCell removes invisible control/format characters (e.g. U+200B, U+FEFF),
+// turns non-breaking spaces into regular spaces and normalizes to NFC
+func sanitizeCell(cell string) string {
+	cell = norm.NFC.String(cell)
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r == '\u00A0':
+			return ' '
+		case r == '\t' || r == '\n' || r == '\r':
+			return r
+		case unicode.Is(unicode.Cc, r) || unicode.Is(unicode.Cf, r):
+			return -1
+		}
+		return r
+	}, cell)
 }
 
-func (importer *ExcelImporter[T]) parseRow(row []string, columnIndexMap map[string]int) (T, error) {
-	var instance T
-	val := reflect.ValueOf(&instance)
-	if val.Kind() == reflect.Ptr {
-		val = val.Elem()
+// reportProgress calls OnProgress every ProgressInterval rows and once more when the sheet is done,
+// emitting the matching EventRowsProcessed or EventCompleted event
+func (importer *ExcelImporter[T]) reportProgress(stats importStats, final bool) {
+	if !final && stats.processed%importer.config.ProgressInterval != 0 {
+		return
 	}
-	if val.Kind() == reflect.Ptr {
-		if val.IsNil() {
-			val.Set(reflect.New(val.Type().Elem()))
-		}
-		val = val.Elem()
+	if importer.config.OnProgress != nil {
+		importer.config.OnProgress(stats.processed)
+	}
+	if final {
+		importer.emit(Event{Kind: EventCompleted, Err: stats.err}, stats)
+	} else {
+		importer.emit(Event{Kind: EventRowsProcessed}, stats)
 	}
+}
 
-	if err := importer.fillStruct(val, row, columnIndexMap, &instance); err != nil {
-		return instance, err
+// padRow extends a row with empty cells up to width. Both excelize's streaming
+// reader and GetRows drop trailing empty cells, so rows can be shorter than the header.
+func padRow(row []string, width int) []string {
+	if len(row) >= width {
+		return row
 	}
+	padded := make([]string, width)
+	copy(padded, row)
+	return padded
+}
 
-	if err := importer.validateData(val); err != nil {
-		return instance, err
+// isCommentRow reports whether a data row starts with CommentPrefix in its first non-empty cell
+func (importer *ExcelImporter[T]) isCommentRow(row []string, rowIndex int) bool {
+	if importer.config.CommentPrefix == "" || rowIndex == importer.config.HeaderRow || rowIndex < importer.config.StartRow {
+		return false
 	}
-	return instance, nil
+	for _, cell := range row {
+		if cell = strings.TrimSpace(cell); cell != "" {
+			return strings.HasPrefix(cell, importer.config.CommentPrefix)
+		}
+	}
+	return false
 }
 
-func (importer *ExcelImporter[T]) buildColumnIndexMap(headerRow []string) map[string]int {
-	indexMap := make(map[string]int)
-	for idx, cellValue := range headerRow {
-		cleanName := strings.Trim(strings.TrimSpace(cellValue), "*")
-		indexMap[cleanName] = idx
+func (importer *ExcelImporter[T]) countCommentRows(rows [][]string) int {
+	if importer.config.CommentPrefix == "" {
+		return 0
 	}
-	return indexMap
+	count := 0
+	for i, row := range rows {
+		if importer.isCommentRow(importer.sanitizeRow(importer.offsetRow(row)), i+1) {
+			count++
+		}
+	}
+	return count
 }
 
 func (importer *ExcelImporter[T]) isEmptyRow(row []string) bool {
 	for _, cell := range row {
-		if strings.TrimSpace(cell) != "" {
+		if strings.TrimSpace(cell) == "" {
+			continue
+		}
+		// Cells outside the projection are not sanitized yet
+		if !importer.config.SanitizeCells || strings.TrimSpace(sanitizeCell(cell)) != "" {
 			return false
 		}
 	}
 	return true
 }
 
-func (importer *ExcelImporter[T]) fillStruct(val reflect.Value, row []string, columnIndexMap map[string]int, instance *T) error {
+func (importer *ExcelImporter[T]) fillStruct(val reflect.Value, row []string, rowIndex int, columnIndexMap map[string]int, instance *T) error {
 	t := val.Type()
 	usedColumns := make(map[int]bool)
 
+	var rawValues reflect.Value
+	if importer.config.RawField != "" {
+		rawValues = val.FieldByName(importer.config.RawField)
+		if !rawValues.IsValid() || rawValues.Type() != reflect.TypeOf(map[string]string{}) {
+			return fmt.Errorf("raw field %s must be a map[string]string", importer.config.RawField)
+		}
+		rawValues.Set(reflect.MakeMap(rawValues.Type()))
+	}
+
+	if importer.config.OnTrailingCells == TrailingCellCapture {
+		field := val.FieldByName(importer.config.TrailingField)
+		if !field.IsValid() || field.Type() != reflect.TypeOf([]string(nil)) {
+			return fmt.Errorf("trailing field %s must be a []string", importer.config.TrailingField)
+		}
+		trailing, _ := trailingCells(row, columnIndexMap)
+		field.Set(reflect.ValueOf(trailing))
+	}
+
 	for i := 0; i < val.NumField(); i++ {
 		field := val.Field(i)
 		fieldType := t.Field(i)
@@ -338,18 +1544,31 @@ func (importer *ExcelImporter[T]) fillStruct(val reflect.Value, row []string, co
 			continue
 		}
 
-		if fieldType.Name == importer.dynamicField {
+		if _, isComment := importer.config.CommentFields[fieldType.Name]; isComment || fieldType.Name == importer.dynamicField {
 			continue
 		}
 
 		excelColumn := importer.findExcelColumnForField(fieldType)
+		spec, combined := importer.config.CombineColumns[fieldType.Name]
+		if combined {
+			excelColumn = spec.combineColumn()
+		}
+		if lookup, ok := importer.config.Lookups[fieldType.Name]; ok {
+			excelColumn = lookup.Column
+		}
 		if excelColumn == "" {
 			continue
 		}
 
-		colIndex, exists := columnIndexMap[excelColumn]
+		colIndex, exists := importer.mappedColumn(columnIndexMap, excelColumn)
+		if combined {
+			exists = spec.hasAnyColumn(columnIndexMap)
+		}
 		if !exists {
-			if defaultValue, hasDefault := importer.config.DefaultValues[fieldType.Name]; hasDefault {
+			if importer.config.OnMissingColumn == MissingColumnIgnore {
+				continue
+			}
+			if defaultValue, hasDefault := importer.defaultFor(fieldType.Name, true); hasDefault {
 				if err := importer.setFieldValue(field, defaultValue); err != nil {
 					return err
 				}
@@ -357,15 +1576,42 @@ func (importer *ExcelImporter[T]) fillStruct(val reflect.Value, row []string, co
 			continue
 		}
 
-		usedColumns[colIndex] = true
-
 		var cellValue string
-		if colIndex < len(row) {
-			cellValue = strings.TrimSpace(row[colIndex])
+		if combined {
+			cellValue = combineCells(spec, row, columnIndexMap, usedColumns)
+			if rawValues.IsValid() {
+				rawValues.SetMapIndex(reflect.ValueOf(fieldType.Name), reflect.ValueOf(cellValue))
+			}
+		} else {
+			usedColumns[colIndex] = true
+			if colIndex < len(row) {
+				cellValue = strings.TrimSpace(row[colIndex])
+				if rawValues.IsValid() {
+					rawValues.SetMapIndex(reflect.ValueOf(fieldType.Name), reflect.ValueOf(row[colIndex]))
+				}
+			}
+		}
+		if table, ok := importer.lookupTables[fieldType.Name]; ok {
+			cellValue = strings.TrimSpace(table[cellValue])
+		}
+		if set, ok := importer.config.TrimSets[fieldType.Name]; ok {
+			cellValue = strings.TrimSpace(strings.Trim(cellValue, set))
+		}
+		if transform, ok := importer.config.PreTransforms[fieldType.Name]; ok {
+			cellValue = strings.TrimSpace(transform(cellValue))
+		}
+		if importer.isNullValue(cellValue) {
+			cellValue = ""
 		}
 
 		if cellValue == "" {
-			if defaultValue, hasDefault := importer.config.DefaultValues[fieldType.Name]; hasDefault {
+			switch importer.config.OnEmptyCell {
+			case EmptyCellError:
+				return fmt.Errorf("field %s: column %s is empty", fieldType.Name, excelColumn)
+			case EmptyCellZero:
+				continue
+			}
+			if defaultValue, hasDefault := importer.defaultFor(fieldType.Name, false); hasDefault {
 				if err := importer.setFieldValue(field, defaultValue); err != nil {
 					return err
 				}
@@ -373,74 +1619,45 @@ func (importer *ExcelImporter[T]) fillStruct(val reflect.Value, row []string, co
 			continue
 		}
 
-		if err := importer.convertAndSetField(field, fieldType, cellValue); err != nil {
-			return fmt.Errorf("field %s conversion failed: %v", fieldType.Name, err)
+		if converter, exists := importer.config.RowConverters[fieldType.Name]; exists {
+			convertedValue, err := converter(cellValue, row, columnIndexMap)
+			if err == nil {
+				err = importer.setConverted(field, convertedValue)
+			}
+			if err != nil {
+				return fmt.Errorf("field %s conversion failed: %v", fieldType.Name, err)
+			}
+			continue
+		}
+
+		// Fast path: plain string fields need no conversion
+		if importer.allStrings {
+			if _, hasConverter := importer.config.CustomConverters[fieldType.Name]; !hasConverter {
+				field.SetString(cellValue)
+				continue
+			}
+		}
+
+		// Combined and looked up values are text whatever the source cells hold
+		numeric := !combined && importer.lookupTables[fieldType.Name] == nil && importer.storedNumber(rowIndex, colIndex)
+		if err := importer.convertAndSetField(field, fieldType, cellValue, numeric); err != nil {
+			if importer.config.LenientKinds && errors.Is(err, ErrUnsupportedKind) {
+				importer.warn(Warning{RowIndex: rowIndex, Column: excelColumn, Message: fmt.Sprintf("field %s left empty: %v", fieldType.Name, err)})
+				continue
+			}
+			return fmt.Errorf("field %s conversion failed: %w", fieldType.Name, err)
 		}
 	}
 
+	if err := importer.fillColumnGroups(val, row, rowIndex, columnIndexMap, usedColumns); err != nil {
+		return err
+	}
+
 	// Handle dynamic field
 	if importer.dynamicField != "" {
 		field := val.FieldByName(importer.dynamicField)
-		if field.IsValid() && field.CanSet() && field.Kind() == reflect.Map {
-			if field.IsNil() {
-				field.Set(reflect.MakeMap(field.Type()))
-			}
-			
-			// Only support map[string]string or map[string]any
-			keyKind := field.Type().Key().Kind()
-			elemKind := field.Type().Elem().Kind()
-			
-			if keyKind == reflect.String {
-				for colName, colIdx := range columnIndexMap {
-					if !usedColumns[colIdx] && colIdx < len(row) {
-						// Apply dynamic filter if set
-						if importer.dynamicFilter != nil {
-                            matched := importer.dynamicFilter.MatchString(colName)
-                            if !matched {
-							    continue
-                            }
-						}
-
-						cellVal := strings.TrimSpace(row[colIdx])
-						if cellVal != "" {
-							var valToSet reflect.Value
-							var err error
-
-							switch elemKind {
-							case reflect.String:
-								valToSet = reflect.ValueOf(cellVal)
-							case reflect.Interface:
-								valToSet = reflect.ValueOf(cellVal)
-							case reflect.Float64, reflect.Float32:
-								if f, e := strconv.ParseFloat(cellVal, 64); e == nil {
-									valToSet = reflect.ValueOf(f).Convert(field.Type().Elem())
-								} else {
-									err = e
-								}
-							case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-								if i, e := strconv.ParseInt(cellVal, 10, 64); e == nil {
-									valToSet = reflect.ValueOf(i).Convert(field.Type().Elem())
-								} else {
-									err = e
-								}
-							case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-								if u, e := strconv.ParseUint(cellVal, 10, 64); e == nil {
-									valToSet = reflect.ValueOf(u).Convert(field.Type().Elem())
-								} else {
-									err = e
-								}
-							case reflect.Bool:
-								b := strings.ToLower(cellVal) == "true" || cellVal == "1" || cellVal == "是"
-								valToSet = reflect.ValueOf(b)
-							}
-
-							if err == nil && valToSet.IsValid() {
-								field.SetMapIndex(reflect.ValueOf(colName), valToSet)
-							}
-						}
-					}
-				}
-			}
+		if field.IsValid() && field.CanSet() {
+			importer.fillDynamicField(field, importer.dynamicCells(row, rowIndex, columnIndexMap, usedColumns))
 		}
 	}
 
@@ -453,7 +1670,189 @@ func (importer *ExcelImporter[T]) fillStruct(val reflect.Value, row []string, co
 	return nil
 }
 
+// isNullValue reports whether a trimmed cell matches one of NullValues
+func (importer *ExcelImporter[T]) isNullValue(cellValue string) bool {
+	return importer.nullValues != nil && cellValue != "" && importer.nullValues[strings.ToLower(cellValue)]
+}
+
+// dynamicCell is one unmapped column captured by the dynamic field
+type dynamicCell struct {
+	column  string
+	value   string
+	numeric bool // Stored as a number, so never localized
+}
+
+// dynamicCells returns the non-empty unmapped cells matching the dynamic filter, in header order
+func (importer *ExcelImporter[T]) dynamicCells(row []string, rowIndex int, columnIndexMap map[string]int, usedColumns map[int]bool) []dynamicCell {
+	if importer.config.DynamicKeyColumn != "" {
+		return importer.dynamicKeyedCell(row, rowIndex, columnIndexMap)
+	}
+	indexes := make([]int, 0, len(columnIndexMap))
+	names := make(map[int]string, len(columnIndexMap))
+	for colName, colIdx := range columnIndexMap {
+		if usedColumns[colIdx] || colIdx >= len(row) || colIdx < importer.config.KeyColumns {
+			continue
+		}
+		if importer.dynamicFilter != nil && !importer.dynamicFilter.MatchString(colName) {
+			continue
+		}
+		indexes = append(indexes, colIdx)
+		names[colIdx] = colName
+	}
+	sort.Ints(indexes)
+
+	cells := make([]dynamicCell, 0, len(indexes))
+	for _, colIdx := range indexes {
+		if cellVal := strings.TrimSpace(row[colIdx]); cellVal != "" && !importer.isNullValue(cellVal) {
+			cells = append(cells, dynamicCell{column: names[colIdx], value: cellVal, numeric: importer.storedNumber(rowIndex, colIdx)})
+		}
+	}
+	return cells
+}
+
+// dynamicKeyedCell returns the row's DynamicKeyColumn/DynamicValueColumn pair as
+// a single cell, or nothing when either is empty or the key does not match the
+// dynamic filter
+func (importer *ExcelImporter[T]) dynamicKeyedCell(row []string, rowIndex int, columnIndexMap map[string]int) []dynamicCell {
+	var cell [2]string
+	var valueIdx int
+	for i, excelCol := range []string{importer.config.DynamicKeyColumn, importer.config.DynamicValueColumn} {
+		colIdx, exists := columnIndexMap[excelCol]
+		if !exists || colIdx >= len(row) {
+			return nil
+		}
+		cell[i] = strings.TrimSpace(row[colIdx])
+		if cell[i] == "" || importer.isNullValue(cell[i]) {
+			return nil
+		}
+		valueIdx = colIdx
+	}
+	if importer.dynamicFilter != nil && !importer.dynamicFilter.MatchString(cell[0]) {
+		return nil
+	}
+	return []dynamicCell{{column: cell[0], value: cell[1], numeric: importer.storedNumber(rowIndex, valueIdx)}}
+}
+
+// fillDynamicField stores cells in a map keyed by column, or in a slice of
+// key/value structs (string key first, value second) that keeps header order.
+// Cells that do not convert to the value type are skipped. A CustomConverters
+// entry keyed by the dynamic field's name replaces the built-in conversion.
+func (importer *ExcelImporter[T]) fillDynamicField(field reflect.Value, cells []dynamicCell) {
+	switch field.Kind() {
+	case reflect.Map:
+		if field.Type().Key().Kind() != reflect.String {
+			return
+		}
+		if field.IsNil() {
+			field.Set(reflect.MakeMap(field.Type()))
+		}
+		for _, cell := range cells {
+			if value, ok := importer.dynamicValue(cell.value, cell.numeric, field.Type().Elem()); ok {
+				field.SetMapIndex(reflect.ValueOf(cell.column).Convert(field.Type().Key()), value)
+			}
+		}
+	case reflect.Slice:
+		if !isDynamicFieldType(field.Type()) {
+			return
+		}
+		elemType := field.Type().Elem()
+		entries := reflect.MakeSlice(field.Type(), 0, len(cells))
+		for _, cell := range cells {
+			value, ok := importer.dynamicValue(cell.value, cell.numeric, elemType.Field(1).Type)
+			if !ok {
+				continue
+			}
+			entry := reflect.New(elemType).Elem()
+			entry.Field(0).SetString(cell.column)
+			entry.Field(1).Set(value)
+			entries = reflect.Append(entries, entry)
+		}
+		field.Set(entries)
+	}
+}
+
+// dynamicValue converts a dynamic cell with the dynamic field's converter, if any
+func (importer *ExcelImporter[T]) dynamicValue(cellVal string, numeric bool, t reflect.Type) (reflect.Value, bool) {
+	converter, exists := importer.config.CustomConverters[importer.dynamicField]
+	if !exists {
+		return importer.convertDynamicValue(cellVal, numeric, t)
+	}
+	converted, err := converter(cellVal)
+	if err != nil {
+		return reflect.Value{}, false
+	}
+	value := reflect.New(t).Elem()
+	if err := importer.setFieldValue(value, converted); err != nil {
+		return reflect.Value{}, false
+	}
+	return value, true
+}
+
+// convertDynamicValue converts a dynamic cell to string, interface, numeric, bool
+// or time types. Struct types get every string field set to the cell text and
+// every other field set to the cell converted to its type, when it converts,
+// e.g. struct{ Raw string; Parsed float64 }. Numeric cells, stored as numbers,
+// are not localized.
+func (importer *ExcelImporter[T]) convertDynamicValue(cellVal string, numeric bool, t reflect.Type) (reflect.Value, bool) {
+	switch t.Kind() {
+	case reflect.Struct:
+		if t == reflect.TypeOf(time.Time{}) {
+			loc := importer.config.Location
+			if loc == nil {
+				loc = time.UTC
+			}
+			if timeVal, err := parseTime(cellVal, loc); err == nil {
+				return reflect.ValueOf(timeVal), true
+			}
+			return reflect.Value{}, false
+		}
+		value := reflect.New(t).Elem()
+		for i := 0; i < t.NumField(); i++ {
+			if !t.Field(i).IsExported() {
+				continue
+			}
+			if fieldValue, ok := importer.convertDynamicValue(cellVal, numeric, t.Field(i).Type); ok {
+				value.Field(i).Set(fieldValue)
+			}
+		}
+		return value, true
+	case reflect.String:
+		return reflect.ValueOf(cellVal).Convert(t), true
+	case reflect.Interface:
+		return reflect.ValueOf(cellVal), true
+	}
+
+	if isNumericKind(t.Kind()) && !numeric {
+		var err error
+		if cellVal, err = importer.localizeNumber(cellVal); err != nil {
+			return reflect.Value{}, false
+		}
+	}
+	switch t.Kind() {
+	case reflect.Float64, reflect.Float32:
+		if f, err := strconv.ParseFloat(cellVal, 64); err == nil {
+			return reflect.ValueOf(importer.snapFloat(f)).Convert(t), true
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if i, err := strconv.ParseInt(cellVal, 10, 64); err == nil {
+			return reflect.ValueOf(i).Convert(t), true
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if u, err := strconv.ParseUint(cellVal, 10, 64); err == nil {
+			return reflect.ValueOf(u).Convert(t), true
+		}
+	case reflect.Bool:
+		if b, err := importer.parseBool(cellVal); err == nil {
+			return reflect.ValueOf(b).Convert(t), true
+		}
+	}
+	return reflect.Value{}, false
+}
+
 func (importer *ExcelImporter[T]) findExcelColumnForField(field reflect.StructField) string {
+	if excelCol, ok := importer.fieldColumns[field.Name]; ok && importer.config.FieldMappings[excelCol] == field.Name {
+		return excelCol
+	}
 	for excelCol, structField := range importer.config.FieldMappings {
 		if structField == field.Name {
 			return excelCol
@@ -462,13 +1861,39 @@ func (importer *ExcelImporter[T]) findExcelColumnForField(field reflect.StructFi
 	return ""
 }
 
-func (importer *ExcelImporter[T]) convertAndSetField(field reflect.Value, fieldType reflect.StructField, cellValue string) error {
+// convertAndSetField converts a cell to the field's type. Numeric cells, stored
+// as numbers, are not localized.
+func (importer *ExcelImporter[T]) convertAndSetField(field reflect.Value, fieldType reflect.StructField, cellValue string, numeric bool) error {
 	if converter, exists := importer.config.CustomConverters[fieldType.Name]; exists {
 		convertedValue, err := converter(cellValue)
 		if err != nil {
 			return err
 		}
-		return importer.setFieldValue(field, convertedValue)
+		return importer.setConverted(field, convertedValue)
+	}
+	if field.Kind() == reflect.Ptr {
+		elem := reflect.New(field.Type().Elem())
+		elemType := fieldType
+		elemType.Type = elem.Elem().Type()
+		if err := importer.convertAndSetField(elem.Elem(), elemType, cellValue, numeric); err != nil {
+			return err
+		}
+		field.Set(elem)
+		return nil
+	}
+	if isNumericKind(field.Kind()) && !numeric {
+		localized, err := importer.localizeNumber(cellValue)
+		if err != nil {
+			return err
+		}
+		cellValue = localized
+	}
+	if importer.config.PercentFields[fieldType.Name] {
+		scaled, err := percent100(cellValue, field.Kind())
+		if err != nil {
+			return err
+		}
+		cellValue = scaled
 	}
 	var convertedValue interface{}
 	switch field.Kind() {
@@ -502,35 +1927,223 @@ func (importer *ExcelImporter[T]) convertAndSetField(field reflect.Value, fieldT
 			if err != nil {
 				return fmt.Errorf("invalid float: %s", cellValue)
 			}
-			convertedValue = floatVal
+			convertedValue = importer.snapFloat(floatVal)
 		}
 	case reflect.Bool:
-		convertedValue = strings.ToLower(cellValue) == "true" || cellValue == "1" || cellValue == "是"
+		b, err := importer.parseBool(cellValue)
+		if err != nil {
+			return err
+		}
+		convertedValue = b
 	case reflect.Struct:
 		if fieldType.Type == reflect.TypeOf(time.Time{}) {
-			timeVal, err := time.Parse("2006-01-02", cellValue)
+			loc := importer.config.Location
+			if loc == nil {
+				loc = time.UTC
+			}
+			timeVal, err := parseTime(cellValue, loc)
 			if err != nil {
-				timeVal, err = time.Parse("2006/01/02", cellValue)
-				if err != nil {
-					return fmt.Errorf("invalid time: %s", cellValue)
-				}
+				return err
 			}
 			convertedValue = timeVal
 		} else {
-			return fmt.Errorf("unsupported struct type: %s", fieldType.Type.Name())
+			return setJSONField(field, cellValue)
+		}
+	case reflect.Map, reflect.Slice:
+		return setJSONField(field, cellValue)
+	case reflect.Interface:
+		if !importer.config.LenientKinds || !reflect.TypeOf("").AssignableTo(field.Type()) {
+			return fmt.Errorf("%w: %s", ErrUnsupportedKind, field.Kind())
 		}
+		convertedValue = cellValue
 	default:
-		return fmt.Errorf("unsupported kind: %s", field.Kind())
+		return fmt.Errorf("%w: %s", ErrUnsupportedKind, field.Kind())
 	}
 	return importer.setFieldValue(field, convertedValue)
 }
 
+// setConverted stores a converter's result. With LenientKinds a string field
+// takes a result of any other type formatted by fmt.Sprint.
+func (importer *ExcelImporter[T]) setConverted(field reflect.Value, value any) error {
+	if importer.config.LenientKinds && field.Kind() == reflect.String && value != nil && reflect.TypeOf(value).Kind() != reflect.String {
+		field.SetString(fmt.Sprint(value))
+		return nil
+	}
+	return importer.setFieldValue(field, value)
+}
+
+func isNumericKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// snapFloat reads magnitudes below FloatEpsilon, and negative zero, as 0
+func (importer *ExcelImporter[T]) snapFloat(f float64) float64 {
+	if f == 0 || math.Abs(f) < importer.config.FloatEpsilon {
+		return 0
+	}
+	return f
+}
+
+// withNumberCells returns a copy of the importer that tells cells stored as
+// numbers apart when DecimalSeparator or ThousandsSeparator is set. Those read
+// in the form strconv parses already, so only text cells are localized.
+func (importer *ExcelImporter[T]) withNumberCells(f *excelize.File, sheetName string) *ExcelImporter[T] {
+	if importer.config.DecimalSeparator == "" && importer.config.ThousandsSeparator == "" {
+		return importer
+	}
+
+	call := *importer
+	call.numberCells = func(cell string) bool {
+		cellType, err := f.GetCellType(sheetName, cell)
+		if err != nil {
+			return false
+		}
+		switch cellType {
+		case excelize.CellTypeNumber:
+			return true
+		case excelize.CellTypeUnset:
+			// Numbers are usually written without a type. Covered merged cells
+			// are untyped too, but hold neither a value nor a formula.
+			value, err := f.GetCellValue(sheetName, cell, excelize.Options{RawCellValue: true})
+			if err != nil {
+				return false
+			}
+			formula, _ := f.GetCellFormula(sheetName, cell)
+			return value != "" || formula != ""
+		}
+		return false
+	}
+	return &call
+}
+
+// storedNumber reports whether the row's cell under colIndex is stored as a number
+func (importer *ExcelImporter[T]) storedNumber(rowIndex, colIndex int) bool {
+	if importer.numberCells == nil {
+		return false
+	}
+	cell, err := excelize.CoordinatesToCellName(colIndex+importer.config.StartColumn, rowIndex)
+	return err == nil && importer.numberCells(cell)
+}
+
+// localizeNumber rewrites a number written with DecimalSeparator and
+// ThousandsSeparator into the form strconv parses. Groups after the first must
+// have three digits, so with "." grouping thousands "1.5" is rejected instead
+// of being read as 15. A space separator also matches no-break spaces.
+func (importer *ExcelImporter[T]) localizeNumber(cellValue string) (string, error) {
+	decimal, thousands := importer.config.DecimalSeparator, importer.config.ThousandsSeparator
+	if decimal == "" && thousands == "" {
+		return cellValue, nil
+	}
+	if decimal == "" {
+		decimal = "."
+	}
+
+	intPart, fracPart, hasFrac := strings.Cut(cellValue, decimal)
+	if thousands != "" {
+		if thousands == " " {
+			intPart = strings.NewReplacer("\u00a0", " ", "\u202f", " ").Replace(intPart)
+		}
+		groups := strings.Split(intPart, thousands)
+		for _, group := range groups[1:] {
+			if len(group) != 3 {
+				return "", fmt.Errorf("invalid number: %s", cellValue)
+			}
+		}
+		intPart = strings.Join(groups, "")
+	}
+	if hasFrac {
+		return intPart + "." + fracPart, nil
+	}
+	return intPart, nil
+}
+
+// defaultBoolValues are the recognized bool cell texts when BoolValues is not set
+var defaultBoolValues = map[string]bool{
+	"true": true, "1": true, "是": true, "yes": true, "y": true,
+	"false": false, "0": false, "否": false, "no": false, "n": false,
+}
+
+// parseBool looks the cell up case-insensitively; unrecognized text is an error
+// unless LenientBools is set, in which case it reads as false
+func (importer *ExcelImporter[T]) parseBool(cellValue string) (bool, error) {
+	if value, ok := importer.boolTexts[strings.ToLower(cellValue)]; ok {
+		return value, nil
+	}
+	if importer.config.LenientBools {
+		return false, nil
+	}
+	return false, fmt.Errorf("invalid bool: %s", cellValue)
+}
+
+// percent100 turns a 0-1 fraction into a whole percent, rounded for integer kinds.
+// Cells already showing a percent sign, e.g. "45%", are taken as whole percents.
+func percent100(cellValue string, kind reflect.Kind) (string, error) {
+	text := strings.TrimSpace(strings.TrimSuffix(cellValue, "%"))
+	value, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		return "", fmt.Errorf("invalid percent: %s", cellValue)
+	}
+	if !strings.HasSuffix(cellValue, "%") {
+		value *= 100
+	}
+	// Drop binary noise such as 0.45 * 100 = 45.00000000000001
+	value = math.Round(value*1e9) / 1e9
+
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		value = math.Round(value)
+	}
+	return strconv.FormatFloat(value, 'f', -1, 64), nil
+}
+
+// setJSONField unmarshals a JSON object or array stored in a cell into a struct, map or slice field
+func setJSONField(field reflect.Value, cellValue string) error {
+	if !strings.HasPrefix(cellValue, "{") && !strings.HasPrefix(cellValue, "[") {
+		return fmt.Errorf("invalid json for %s: %s", field.Type(), cellValue)
+	}
+	target := reflect.New(field.Type())
+	if err := json.Unmarshal([]byte(cellValue), target.Interface()); err != nil {
+		return fmt.Errorf("invalid json for %s: %v", field.Type(), err)
+	}
+	field.Set(target.Elem())
+	return nil
+}
+
+// timeLayouts are tried in order when parsing time.Time cells; the first matches the exporter's format
+var timeLayouts = []string{"2006-01-02 15:04:05", "2006-01-02", "2006/01/02 15:04:05", "2006/01/02"}
+
+func parseTime(cellValue string, loc *time.Location) (time.Time, error) {
+	for _, layout := range timeLayouts {
+		if timeVal, err := time.ParseInLocation(layout, cellValue, loc); err == nil {
+			return timeVal, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time: %s", cellValue)
+}
+
 func (importer *ExcelImporter[T]) setFieldValue(field reflect.Value, value interface{}) error {
 	if value == nil {
 		return nil
 	}
 	val := reflect.ValueOf(value)
-	
+
+	// Allocate pointer fields for non-pointer values
+	if field.Kind() == reflect.Ptr && !val.Type().AssignableTo(field.Type()) {
+		elem := reflect.New(field.Type().Elem())
+		if err := importer.setFieldValue(elem.Elem(), value); err != nil {
+			return err
+		}
+		field.Set(elem)
+		return nil
+	}
+
 	// Handle integer type mismatches (e.g. int64 to int)
 	if val.Kind() != field.Kind() && val.Type().ConvertibleTo(field.Type()) {
 		field.Set(val.Convert(field.Type()))
@@ -540,7 +2153,7 @@ func (importer *ExcelImporter[T]) setFieldValue(field reflect.Value, value inter
 	if !val.Type().AssignableTo(field.Type()) {
 		return fmt.Errorf("type mismatch: cannot assign %v to %v", val.Type(), field.Type())
 	}
-	
+
 	field.Set(val)
 	return nil
 }
@@ -559,15 +2172,24 @@ func (importer *ExcelImporter[T]) validateData(instance reflect.Value) error {
 	return nil
 }
 
-func downloadFromUrl(url string) (io.ReadCloser, string, error) {
-	resp, err := http.Get(url)
+func downloadFromUrl(ctx context.Context, url string) (io.ReadCloser, string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("request failed: %w", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
 		return nil, "", fmt.Errorf("request failed: %w", err)
 	}
 
 	if resp.StatusCode != http.StatusOK {
 		_ = resp.Body.Close()
-		return nil, "", fmt.Errorf("status code: %d", resp.StatusCode)
+		return nil, "", &statusError{code: resp.StatusCode}
+	}
+	// Expired sessions often redirect to a login page that is served with 200
+	if contentType := resp.Header.Get("Content-Type"); strings.HasPrefix(contentType, "text/html") {
+		_ = resp.Body.Close()
+		return nil, "", &NotSpreadsheetError{ContentType: contentType}
 	}
 	var fileName string
 	disp := resp.Header.Get("Content-Disposition")
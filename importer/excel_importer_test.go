@@ -1,8 +1,23 @@
 package importer
 
 import (
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"reflect"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/xuri/excelize/v2"
 )
@@ -92,7 +107,7 @@ func TestExcelImporter_Stream(t *testing.T) {
 		if res.Error != nil {
 			t.Fatalf("Stream error at row %d: %v", res.RowIndex, res.Error)
 		}
-		
+
 		count++
 		row := res.Data
 		if row.ClientAccount != "C123" {
@@ -110,3 +125,2305 @@ func TestExcelImporter_Stream(t *testing.T) {
 		t.Fatalf("Expected 1 row, got %d", count)
 	}
 }
+
+func TestExcelImporter_MissingAndEmptyPolicies(t *testing.T) {
+	filename := "test_import_policies.xlsx"
+	createTestExcel(t, filename)
+	defer os.Remove(filename)
+
+	type PolicyRow struct {
+		ClientAccount string `excel:"用户编号"`
+		Region        string `excel:"区域"`
+	}
+
+	// Missing column rejected by default
+	if _, err := NewExcelImporter(&ExcelImportConfig[PolicyRow]{}).ImportLocal(filename); err == nil {
+		t.Fatal("Expected missing column error")
+	}
+
+	// Missing column falls back to default when configured
+	rows, err := NewExcelImporter(&ExcelImportConfig[PolicyRow]{
+		OnMissingColumn: MissingColumnDefault,
+		DefaultValues:   map[string]any{"Region": "N/A"},
+	}).ImportLocal(filename)
+	if err != nil {
+		t.Fatalf("ImportLocal failed: %v", err)
+	}
+	if len(rows) != 1 || rows[0].Region != "N/A" {
+		t.Errorf("Expected Region default N/A, got %+v", rows)
+	}
+
+	// Missing column ignored leaves zero value
+	rows, err = NewExcelImporter(&ExcelImportConfig[PolicyRow]{
+		OnMissingColumn: MissingColumnIgnore,
+		DefaultValues:   map[string]any{"Region": "N/A"},
+	}).ImportLocal(filename)
+	if err != nil {
+		t.Fatalf("ImportLocal failed: %v", err)
+	}
+	if len(rows) != 1 || rows[0].Region != "" {
+		t.Errorf("Expected empty Region, got %+v", rows)
+	}
+
+	// The column is present but the cell is empty
+	emptyFile := "test_import_policies_empty.xlsx"
+	f := excelize.NewFile()
+	_ = f.SetSheetRow("Sheet1", "A1", &[]string{"用户编号", "区域"})
+	_ = f.SetSheetRow("Sheet1", "A2", &[]string{"C1", ""})
+	if err := f.SaveAs(emptyFile); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(emptyFile)
+
+	rows, err = NewExcelImporter(&ExcelImportConfig[PolicyRow]{
+		DefaultValues: map[string]any{"Region": "N/A"},
+	}).ImportLocal(emptyFile)
+	if err != nil || len(rows) != 1 || rows[0].Region != "N/A" {
+		t.Errorf("Expected Region default N/A for the empty cell, got %+v, %v", rows, err)
+	}
+
+	_, err = NewExcelImporter(&ExcelImportConfig[PolicyRow]{
+		OnEmptyCell:   EmptyCellError,
+		DefaultValues: map[string]any{"Region": "N/A"},
+	}).ImportLocal(emptyFile)
+	if err == nil || !strings.Contains(err.Error(), "column 区域 is empty") {
+		t.Errorf("Expected empty cell error, got %v", err)
+	}
+
+	rows, err = NewExcelImporter(&ExcelImportConfig[PolicyRow]{
+		OnEmptyCell:   EmptyCellZero,
+		DefaultValues: map[string]any{"Region": "N/A"},
+	}).ImportLocal(emptyFile)
+	if err != nil || len(rows) != 1 || rows[0].ClientAccount != "C1" || rows[0].Region != "" {
+		t.Errorf("Expected zero Region ignoring the default, got %+v, %v", rows, err)
+	}
+}
+
+func TestExcelImporter_ImportAllSheetsOverrides(t *testing.T) {
+	filename := "test_import_all_sheets.xlsx"
+	f := excelize.NewFile()
+	_ = f.SetSheetName("Sheet1", "Summary")
+	_, _ = f.NewSheet("Detail")
+	_ = f.SetSheetRow("Summary", "A1", &[]string{"用户编号", "日期"})
+	_ = f.SetSheetRow("Summary", "A2", &[]string{"S1", "2023-10-01"})
+	_ = f.SetSheetRow("Detail", "A1", &[]string{"明细报表"})
+	_ = f.SetSheetRow("Detail", "A4", &[]string{"用户编号", "日期"})
+	_ = f.SetSheetRow("Detail", "A5", &[]string{"D1", "2023-10-02"})
+	if err := f.SaveAs(filename); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(filename)
+
+	importer := NewExcelImporter(&ExcelImportConfig[TestRow]{})
+	names, err := importer.SheetNamesLocal(filename)
+	if err != nil {
+		t.Fatalf("SheetNamesLocal failed: %v", err)
+	}
+	if len(names) != 2 || names[0] != "Summary" || names[1] != "Detail" {
+		t.Errorf("Unexpected sheet names: %v", names)
+	}
+
+	result, err := importer.ImportAllSheetsLocal(filename, map[string]*ExcelImportConfig[TestRow]{
+		"Detail": {HeaderRow: 4, StartRow: 5},
+	})
+	if err != nil {
+		t.Fatalf("ImportAllSheetsLocal failed: %v", err)
+	}
+	if len(result["Summary"]) != 1 || result["Summary"][0].ClientAccount != "S1" {
+		t.Errorf("Unexpected Summary rows: %+v", result["Summary"])
+	}
+	if len(result["Detail"]) != 1 || result["Detail"][0].ClientAccount != "D1" {
+		t.Errorf("Unexpected Detail rows: %+v", result["Detail"])
+	}
+}
+
+func TestExcelImporter_ValidateLocal(t *testing.T) {
+	filename := "test_import_validate.xlsx"
+	createTestExcel(t, filename)
+	defer os.Remove(filename)
+
+	type ValidateRow struct {
+		ClientAccount string `excel:"用户编号"`
+		Slot          int    `excel:"00:30"`
+	}
+
+	importer := NewExcelImporter(&ExcelImportConfig[ValidateRow]{
+		Validators: map[string]func(any) error{
+			"Slot": func(v any) error {
+				if v.(int) < 150 {
+					return fmt.Errorf("slot too small")
+				}
+				return nil
+			},
+		},
+	})
+	errs := importer.ValidateLocal(filename)
+	if len(errs) != 1 {
+		t.Fatalf("Expected 1 error, got %v", errs)
+	}
+	if errs[0].RowIndex != 2 {
+		t.Errorf("Expected error on row 2, got %d", errs[0].RowIndex)
+	}
+}
+
+func TestExcelImporter_Limits(t *testing.T) {
+	filename := "test_import_limits.xlsx"
+	createTestExcel(t, filename)
+	defer os.Remove(filename)
+
+	if _, err := NewExcelImporter(&ExcelImportConfig[TestRow]{MaxCellLength: 5}).ImportLocal(filename); err == nil {
+		t.Error("Expected max cell length error")
+	}
+	if _, err := NewExcelImporter(&ExcelImportConfig[TestRow]{MaxColumns: 3}).ImportLocal(filename); err == nil {
+		t.Error("Expected max columns error")
+	}
+	if _, err := NewExcelImporter(&ExcelImportConfig[TestRow]{MaxRows: 1}).ImportLocal(filename); err == nil {
+		t.Error("Expected max rows error")
+	}
+	if _, err := NewExcelImporter(&ExcelImportConfig[TestRow]{MaxCellLength: 64, MaxColumns: 5, MaxRows: 2}).ImportLocal(filename); err != nil {
+		t.Errorf("Unexpected error within limits: %v", err)
+	}
+
+	// Limits apply while reading, so an oversized sheet is never loaded whole
+	f := excelize.NewFile()
+	defer f.Close()
+	_ = f.SetSheetRow("Sheet1", "A1", &[]string{"Name"})
+	for row := 2; row <= 1000; row++ {
+		_ = f.SetCellValue("Sheet1", fmt.Sprintf("A%d", row), "x")
+	}
+	_ = f.SetCellValue("Sheet1", "A4", strings.Repeat("x", 10))
+	rows, err := NewExcelImporter(&ExcelImportConfig[TestRow]{MaxRows: 10}).readRows(f, "Sheet1")
+	if err != nil || len(rows) != 11 {
+		t.Errorf("Expected reading to stop past max rows, got %d rows, %v", len(rows), err)
+	}
+	rows, err = NewExcelImporter(&ExcelImportConfig[TestRow]{MaxCellLength: 5}).readRows(f, "Sheet1")
+	if err != nil || len(rows) != 4 {
+		t.Errorf("Expected reading to stop at the long cell, got %d rows, %v", len(rows), err)
+	}
+	skipped := NewExcelImporter(&ExcelImportConfig[TestRow]{MaxCellLength: 5, SkipRows: map[int]bool{4: true}})
+	if rows, err = skipped.readRows(f, "Sheet1"); err != nil || len(rows) != 1000 {
+		t.Errorf("Expected a skipped long cell to be read past, got %d rows, %v", len(rows), err)
+	}
+}
+
+func TestExcelImporter_SanitizeCells(t *testing.T) {
+	filename := "test_import_sanitize.xlsx"
+	f := excelize.NewFile()
+	_ = f.SetSheetRow("Sheet1", "A1", &[]string{"用户编号\u200b", "\ufeff日期"})
+	_ = f.SetSheetRow("Sheet1", "A2", &[]string{"C1\u200b", "2023-10-01\u00a0"})
+	if err := f.SaveAs(filename); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(filename)
+
+	if _, err := NewExcelImporter(&ExcelImportConfig[TestRow]{}).ImportLocal(filename); err == nil {
+		t.Error("Expected header mismatch without sanitization")
+	}
+
+	rows, err := NewExcelImporter(&ExcelImportConfig[TestRow]{SanitizeCells: true}).ImportLocal(filename)
+	if err != nil {
+		t.Fatalf("ImportLocal failed: %v", err)
+	}
+	if len(rows) != 1 || rows[0].ClientAccount != "C1" || rows[0].Date != "2023-10-01" {
+		t.Errorf("Unexpected rows: %+v", rows)
+	}
+}
+
+func TestIsLegacyXLS(t *testing.T) {
+	legacy, err := isLegacyXLS(bytes.NewReader([]byte{0xD0, 0xCF, 0x11, 0xE0, 0xA1, 0xB1, 0x1A, 0xE1}))
+	if err != nil || !legacy {
+		t.Errorf("Expected BIFF signature to be detected, got %v, %v", legacy, err)
+	}
+	legacy, err = isLegacyXLS(bytes.NewReader([]byte("PK\x03\x04")))
+	if err != nil || legacy {
+		t.Errorf("Expected zip signature not to be detected, got %v, %v", legacy, err)
+	}
+}
+
+func TestExcelImporter_LegacyXLS(t *testing.T) {
+	type OrderRow struct {
+		Name string `excel:"名称"`
+		Qty  int    `excel:"数量"`
+	}
+	// testdata/legacy.xls is a BIFF8 workbook with sheets 订单 and 退货
+	filename := "testdata/legacy.xls"
+
+	data, err := NewExcelImporter(&ExcelImportConfig[OrderRow]{}).ImportLocal(filename)
+	if err != nil {
+		t.Fatalf("ImportLocal failed: %v", err)
+	}
+	if fmt.Sprint(data) != "[{苹果 3} {香蕉 5}]" {
+		t.Errorf("Unexpected rows: %v", data)
+	}
+
+	data, err = NewExcelImporter(&ExcelImportConfig[OrderRow]{SheetName: "退货"}).ImportLocal(filename)
+	if err != nil || fmt.Sprint(data) != "[{苹果 1}]" {
+		t.Errorf("Expected the named sheet, got %v, %v", data, err)
+	}
+
+	all, err := NewExcelImporter(&ExcelImportConfig[OrderRow]{}).ImportAllSheetsLocal(filename, nil)
+	if err != nil {
+		t.Fatalf("ImportAllSheetsLocal failed: %v", err)
+	}
+	if fmt.Sprint(all) != "map[订单:[{苹果 3} {香蕉 5}] 退货:[{苹果 1}]]" {
+		t.Errorf("Unexpected sheets: %v", all)
+	}
+
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var compressed bytes.Buffer
+	zw := gzip.NewWriter(&compressed)
+	_, _ = zw.Write(content)
+	_ = zw.Close()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(compressed.Bytes())
+	}))
+	defer server.Close()
+
+	all, err = NewExcelImporter(&ExcelImportConfig[OrderRow]{}).ImportAllSheets(server.URL, nil)
+	if err != nil || len(all["订单"]) != 2 || len(all["退货"]) != 1 {
+		t.Errorf("Expected every sheet of a gzipped .xls, got %v, %v", all, err)
+	}
+}
+
+func TestExcelImporter_Cursor(t *testing.T) {
+	filename := "test_import_cursor.xlsx"
+	createTestExcel(t, filename)
+	defer os.Remove(filename)
+
+	c, err := NewExcelImporter(&ExcelImportConfig[TestRow]{}).CursorLocal(filename)
+	if err != nil {
+		t.Fatalf("CursorLocal failed: %v", err)
+	}
+	defer c.Close()
+
+	var count int
+	for c.Next() {
+		row, err := c.Scan()
+		if err != nil {
+			t.Fatalf("Scan error at row %d: %v", c.RowIndex(), err)
+		}
+		if row.ClientAccount != "C123" {
+			t.Errorf("Expected ClientAccount C123, got %s", row.ClientAccount)
+		}
+		count++
+	}
+	if err := c.Err(); err != nil {
+		t.Fatalf("Cursor error: %v", err)
+	}
+	if count != 1 {
+		t.Fatalf("Expected 1 row, got %d", count)
+	}
+}
+
+func TestExcelImporter_Location(t *testing.T) {
+	filename := "test_import_location.xlsx"
+	createTestExcel(t, filename)
+	defer os.Remove(filename)
+
+	type TimeRow struct {
+		Date time.Time `excel:"日期"`
+	}
+
+	loc := time.FixedZone("UTC+8", 8*3600)
+	rows, err := NewExcelImporter(&ExcelImportConfig[TimeRow]{Location: loc}).ImportLocal(filename)
+	if err != nil {
+		t.Fatalf("ImportLocal failed: %v", err)
+	}
+	want := time.Date(2023, 10, 1, 0, 0, 0, 0, loc)
+	if len(rows) != 1 || !rows[0].Date.Equal(want) {
+		t.Errorf("Expected %v, got %+v", want, rows)
+	}
+}
+
+func TestExcelImporter_RowNum(t *testing.T) {
+	filename := "test_import_rownum.xlsx"
+	createTestExcel(t, filename)
+	defer os.Remove(filename)
+
+	type RowNumRow struct {
+		Row           int    `excel:"rownum"`
+		ClientAccount string `excel:"用户编号"`
+	}
+
+	rows, err := NewExcelImporter(&ExcelImportConfig[RowNumRow]{}).ImportLocal(filename)
+	if err != nil {
+		t.Fatalf("ImportLocal failed: %v", err)
+	}
+	if len(rows) != 1 || rows[0].Row != 2 {
+		t.Errorf("Expected row number 2, got %+v", rows)
+	}
+
+	for res := range NewExcelImporter(&ExcelImportConfig[RowNumRow]{}).ImportStreamLocal(filename) {
+		if res.Error != nil || res.Data.Row != res.RowIndex {
+			t.Errorf("Expected streamed row number %d, got %+v", res.RowIndex, res)
+		}
+	}
+}
+
+func TestExcelImporter_RawRow(t *testing.T) {
+	filename := "test_import_rawrow.xlsx"
+	createTestExcel(t, filename)
+	defer os.Remove(filename)
+
+	type RawRowRow struct {
+		ClientAccount string   `excel:"用户编号"`
+		Cells         []string `excel:"rawrow"`
+	}
+
+	rows, err := NewExcelImporter(&ExcelImportConfig[RawRowRow]{}).ImportLocal(filename)
+	if err != nil {
+		t.Fatalf("ImportLocal failed: %v", err)
+	}
+	if len(rows) != 1 || len(rows[0].Cells) == 0 || rows[0].Cells[0] != rows[0].ClientAccount {
+		t.Fatalf("Expected the original cells, got %+v", rows)
+	}
+
+	_, err = NewExcelImporterE(&ExcelImportConfig[RawRowRow]{RawRowField: "ClientAccount"})
+	if err == nil {
+		t.Error("Expected error for a raw row field that is not a []string")
+	}
+}
+
+func TestExcelImporter_RaggedRows(t *testing.T) {
+	filename := "test_import_ragged.xlsx"
+	f := excelize.NewFile()
+	_ = f.SetSheetRow("Sheet1", "A1", &[]string{"用户编号", "日期", "00:30", "01:00", "01:30"})
+	_ = f.SetSheetRow("Sheet1", "A2", &[]string{"C1", "2023-10-01", "100"})
+	_ = f.SetSheetRow("Sheet1", "A3", &[]string{"C2", "2023-10-01", "100", "200", "300"})
+	if err := f.SaveAs(filename); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(filename)
+
+	var widths []int
+	config := &ExcelImportConfig[TestRow]{
+		RowHook: func(row *TestRow, cells []string, columnIndexMap map[string]int) error {
+			widths = append(widths, len(cells))
+			_ = cells[columnIndexMap["01:30"]]
+			return nil
+		},
+	}
+	importer := NewExcelImporter(config)
+
+	rows, err := importer.ImportLocal(filename)
+	if err != nil {
+		t.Fatalf("ImportLocal failed: %v", err)
+	}
+	if len(rows) != 2 || len(rows[0].TimeData) != 1 || len(rows[1].TimeData) != 3 {
+		t.Errorf("Unexpected dynamic data: %+v", rows)
+	}
+
+	for res := range importer.ImportStreamLocal(filename) {
+		if res.Error != nil {
+			t.Fatalf("Stream error at row %d: %v", res.RowIndex, res.Error)
+		}
+	}
+
+	for _, width := range widths {
+		if width != 5 {
+			t.Errorf("Expected rows padded to header width 5, got %v", widths)
+			break
+		}
+	}
+}
+
+func TestExcelImporter_OnProgress(t *testing.T) {
+	filename := "test_import_progress.xlsx"
+	f := excelize.NewFile()
+	_ = f.SetSheetRow("Sheet1", "A1", &[]string{"用户编号", "日期"})
+	for i := 2; i <= 6; i++ {
+		cell, _ := excelize.CoordinatesToCellName(1, i)
+		_ = f.SetSheetRow("Sheet1", cell, &[]string{fmt.Sprintf("C%d", i), "2023-10-01"})
+	}
+	if err := f.SaveAs(filename); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(filename)
+
+	var calls []int
+	importer := NewExcelImporter(&ExcelImportConfig[TestRow]{
+		ProgressInterval: 2,
+		OnProgress:       func(n int) { calls = append(calls, n) },
+	})
+
+	if _, err := importer.ImportLocal(filename); err != nil {
+		t.Fatalf("ImportLocal failed: %v", err)
+	}
+	if fmt.Sprint(calls) != "[2 4 5]" {
+		t.Errorf("Unexpected batch progress calls: %v", calls)
+	}
+
+	calls = nil
+	for range importer.ImportStreamLocal(filename) {
+	}
+	if fmt.Sprint(calls) != "[2 4 5]" {
+		t.Errorf("Unexpected stream progress calls: %v", calls)
+	}
+}
+
+func TestExcelImporter_GzipInput(t *testing.T) {
+	filename := "test_import_gzip.xlsx"
+	createTestExcel(t, filename)
+	defer os.Remove(filename)
+
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var compressed bytes.Buffer
+	zw := gzip.NewWriter(&compressed)
+	_, _ = zw.Write(content)
+	_ = zw.Close()
+
+	rows, err := NewExcelImporter(&ExcelImportConfig[TestRow]{}).ImportReader(bytes.NewReader(compressed.Bytes()))
+	if err != nil {
+		t.Fatalf("ImportReader failed: %v", err)
+	}
+	if len(rows) != 1 || rows[0].ClientAccount != "C123" {
+		t.Errorf("Unexpected rows: %+v", rows)
+	}
+
+	if _, err := NewExcelImporter(&ExcelImportConfig[TestRow]{Compression: CompressionNone}).ImportReader(bytes.NewReader(compressed.Bytes())); err == nil {
+		t.Error("Expected error when decompression is disabled")
+	}
+}
+
+func TestExcelImporter_JSONCell(t *testing.T) {
+	filename := "test_import_json.xlsx"
+	f := excelize.NewFile()
+	_ = f.SetSheetRow("Sheet1", "A1", &[]string{"用户编号", "元数据", "标签"})
+	_ = f.SetSheetRow("Sheet1", "A2", &[]string{"C1", `{"source":"api","version":2}`, `["a","b"]`})
+	_ = f.SetSheetRow("Sheet1", "A3", &[]string{"C2", `not json`, `[]`})
+	if err := f.SaveAs(filename); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(filename)
+
+	type Metadata struct {
+		Source  string `json:"source"`
+		Version int    `json:"version"`
+	}
+	type JSONRow struct {
+		ClientAccount string   `excel:"用户编号"`
+		Metadata      Metadata `excel:"元数据"`
+		Tags          []string `excel:"标签"`
+	}
+
+	var results []ImportResult[JSONRow]
+	for res := range NewExcelImporter(&ExcelImportConfig[JSONRow]{}).ImportStreamLocal(filename) {
+		results = append(results, res)
+	}
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 results, got %d", len(results))
+	}
+	if results[0].Error != nil || results[0].Data.Metadata != (Metadata{Source: "api", Version: 2}) || len(results[0].Data.Tags) != 2 {
+		t.Errorf("Unexpected first row: %+v", results[0])
+	}
+	if results[1].Error == nil {
+		t.Error("Expected error for non-JSON cell")
+	}
+}
+
+func TestExcelImporter_OptionalColumns(t *testing.T) {
+	filename := "test_import_optional.xlsx"
+	createTestExcel(t, filename)
+	defer os.Remove(filename)
+
+	type OptionalRow struct {
+		ClientAccount string  `excel:"用户编号"`
+		Discount      float64 `excel:"折扣,optional"`
+		Region        string  `excel:"区域"`
+	}
+
+	var warnings []Warning
+	importer := NewExcelImporter(&ExcelImportConfig[OptionalRow]{
+		OptionalColumns: []string{"区域"},
+		DefaultValues:   map[string]any{"Discount": 1.0},
+		OnWarning:       func(w Warning) { warnings = append(warnings, w) },
+	})
+	rows, err := importer.ImportLocal(filename)
+	if err != nil {
+		t.Fatalf("ImportLocal failed: %v", err)
+	}
+	if len(rows) != 1 || rows[0].Discount != 1.0 || rows[0].Region != "" {
+		t.Errorf("Unexpected rows: %+v", rows)
+	}
+	if len(warnings) != 2 {
+		t.Errorf("Expected 2 warnings, got %v", warnings)
+	}
+
+	// Tagged columns must not land in spare capacity of the caller's slice
+	shared := make([]string, 1, 4)
+	shared[0] = "区域"
+	NewExcelImporter(&ExcelImportConfig[OptionalRow]{OptionalColumns: shared})
+	if extended := shared[:2]; extended[1] != "" {
+		t.Errorf("Expected the caller's OptionalColumns untouched, got %q", extended)
+	}
+}
+
+func TestExcelImporter_RawField(t *testing.T) {
+	filename := "test_import_raw.xlsx"
+	f := excelize.NewFile()
+	_ = f.SetSheetRow("Sheet1", "A1", &[]string{"用户编号", "金额"})
+	_ = f.SetSheetRow("Sheet1", "A2", &[]string{" C1 ", "1.50"})
+	if err := f.SaveAs(filename); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(filename)
+
+	type RawRow struct {
+		ClientAccount string            `excel:"用户编号"`
+		Amount        float64           `excel:"金额"`
+		Raw           map[string]string `excel:"raw"`
+	}
+
+	rows, err := NewExcelImporter(&ExcelImportConfig[RawRow]{}).ImportLocal(filename)
+	if err != nil {
+		t.Fatalf("ImportLocal failed: %v", err)
+	}
+	if len(rows) != 1 || rows[0].Amount != 1.5 {
+		t.Fatalf("Unexpected rows: %+v", rows)
+	}
+	if rows[0].Raw["ClientAccount"] != " C1 " || rows[0].Raw["Amount"] != "1.50" {
+		t.Errorf("Unexpected raw values: %v", rows[0].Raw)
+	}
+}
+
+func TestExcelImporter_InputPassword(t *testing.T) {
+	filename := "test_import_password.xlsx"
+	f := excelize.NewFile()
+	_ = f.SetSheetRow("Sheet1", "A1", &[]string{"用户编号", "日期"})
+	_ = f.SetSheetRow("Sheet1", "A2", &[]string{"C1", "2023-10-01"})
+	if err := f.SaveAs(filename, excelize.Options{Password: "secret"}); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(filename)
+
+	if _, err := NewExcelImporter(&ExcelImportConfig[TestRow]{}).ImportLocal(filename); err == nil {
+		t.Error("Expected error without password")
+	}
+
+	importer := NewExcelImporter(&ExcelImportConfig[TestRow]{InputPassword: "secret"})
+	rows, err := importer.ImportLocal(filename)
+	if err != nil {
+		t.Fatalf("ImportLocal failed: %v", err)
+	}
+	if len(rows) != 1 || rows[0].ClientAccount != "C1" {
+		t.Errorf("Unexpected rows: %+v", rows)
+	}
+	for res := range importer.ImportStreamLocal(filename) {
+		if res.Error != nil {
+			t.Errorf("Stream error: %v", res.Error)
+		}
+	}
+}
+
+func TestExcelImporter_RawCellValues(t *testing.T) {
+	filename := "test_import_raw_values.xlsx"
+	f := excelize.NewFile()
+	_ = f.SetSheetRow("Sheet1", "A1", &[]string{"用户编号", "金额"})
+	_ = f.SetCellValue("Sheet1", "A2", "C1")
+	_ = f.SetCellValue("Sheet1", "B2", 1234)
+	format := "#,##0.00"
+	styleID, _ := f.NewStyle(&excelize.Style{CustomNumFmt: &format})
+	_ = f.SetCellStyle("Sheet1", "B2", "B2", styleID)
+	if err := f.SaveAs(filename); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(filename)
+
+	type AmountRow struct {
+		ClientAccount string  `excel:"用户编号"`
+		Amount        float64 `excel:"金额"`
+	}
+
+	if _, err := NewExcelImporter(&ExcelImportConfig[AmountRow]{}).ImportLocal(filename); err == nil {
+		t.Error("Expected formatted value 1,234.00 to fail float parsing")
+	}
+
+	importer := NewExcelImporter(&ExcelImportConfig[AmountRow]{RawCellValues: true})
+	rows, err := importer.ImportLocal(filename)
+	if err != nil {
+		t.Fatalf("ImportLocal failed: %v", err)
+	}
+	if len(rows) != 1 || rows[0].Amount != 1234 {
+		t.Errorf("Unexpected rows: %+v", rows)
+	}
+	for res := range importer.ImportStreamLocal(filename) {
+		if res.Error != nil || res.Data.Amount != 1234 {
+			t.Errorf("Unexpected stream result: %+v", res)
+		}
+	}
+}
+
+func TestExcelImporter_DefaultSpecs(t *testing.T) {
+	filename := "test_import_default_specs.xlsx"
+	f := excelize.NewFile()
+	_ = f.SetSheetRow("Sheet1", "A1", &[]string{"用户编号", "区域"})
+	_ = f.SetSheetRow("Sheet1", "A2", &[]string{"C1", ""})
+	if err := f.SaveAs(filename); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(filename)
+
+	type SpecRow struct {
+		ClientAccount string `excel:"用户编号"`
+		Region        string `excel:"区域"`
+		Level         string `excel:"等级"`
+	}
+
+	// Empty-only default applies to the blank cell
+	rows, err := NewExcelImporter(&ExcelImportConfig[SpecRow]{
+		OnMissingColumn: MissingColumnIgnore,
+		DefaultSpecs:    map[string]DefaultSpec{"Region": {Value: "N/A", EmptyOnly: true}},
+	}).ImportLocal(filename)
+	if err != nil {
+		t.Fatalf("ImportLocal failed: %v", err)
+	}
+	if len(rows) != 1 || rows[0].Region != "N/A" {
+		t.Errorf("Unexpected rows: %+v", rows)
+	}
+
+	// Empty-only default makes its missing column fatal despite MissingColumnIgnore
+	_, err = NewExcelImporter(&ExcelImportConfig[SpecRow]{
+		OnMissingColumn: MissingColumnIgnore,
+		DefaultSpecs:    map[string]DefaultSpec{"Level": {Value: "L1", EmptyOnly: true}},
+	}).ImportLocal(filename)
+	if err == nil {
+		t.Error("Expected missing column error for empty-only default")
+	}
+}
+
+// WideStringRow is a wide all-string row used to benchmark the string fast path
+type WideStringRow struct {
+	F01 string `excel:"C01"`
+	F02 string `excel:"C02"`
+	F03 string `excel:"C03"`
+	F04 string `excel:"C04"`
+	F05 string `excel:"C05"`
+	F06 string `excel:"C06"`
+	F07 string `excel:"C07"`
+	F08 string `excel:"C08"`
+	F09 string `excel:"C09"`
+	F10 string `excel:"C10"`
+	F11 string `excel:"C11"`
+	F12 string `excel:"C12"`
+	F13 string `excel:"C13"`
+	F14 string `excel:"C14"`
+	F15 string `excel:"C15"`
+	F16 string `excel:"C16"`
+	F17 string `excel:"C17"`
+	F18 string `excel:"C18"`
+	F19 string `excel:"C19"`
+	F20 string `excel:"C20"`
+}
+
+func benchmarkWideStringRows(b *testing.B, fastPath bool) {
+	importer := NewExcelImporter(&ExcelImportConfig[WideStringRow]{})
+	importer.allStrings = fastPath
+
+	header := make([]string, 20)
+	row := make([]string, 20)
+	for i := range header {
+		header[i] = fmt.Sprintf("C%02d", i+1)
+		row[i] = fmt.Sprintf("value-%d", i)
+	}
+	columnIndexMap := importer.buildColumnIndexMap(header)
+
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := importer.parseRow(row, 2, columnIndexMap); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkParseRow_WideStrings(b *testing.B) {
+	b.Run("FastPath", func(b *testing.B) { benchmarkWideStringRows(b, true) })
+	b.Run("Generic", func(b *testing.B) { benchmarkWideStringRows(b, false) })
+}
+
+func TestExcelImporter_ImportFromFile(t *testing.T) {
+	f := excelize.NewFile()
+	defer f.Close()
+	_ = f.SetSheetRow("Sheet1", "A1", &[]string{"用户编号", "日期"})
+	_ = f.SetSheetRow("Sheet1", "A2", &[]string{"C1", "2023-10-01"})
+	_, _ = f.NewSheet("Config")
+	_ = f.SetSheetRow("Config", "A1", &[]string{"键", "值"})
+	_ = f.SetSheetRow("Config", "A2", &[]string{"mode", "strict"})
+
+	type ConfigRow struct {
+		Key   string `excel:"键"`
+		Value string `excel:"值"`
+	}
+
+	configs, err := NewExcelImporter(&ExcelImportConfig[ConfigRow]{SheetName: "Config"}).ImportFromFile(f)
+	if err != nil {
+		t.Fatalf("ImportFromFile failed: %v", err)
+	}
+	if len(configs) != 1 || configs[0].Value != "strict" {
+		t.Errorf("Unexpected config rows: %+v", configs)
+	}
+
+	var count int
+	for res := range NewExcelImporter(&ExcelImportConfig[TestRow]{SheetName: "Sheet1"}).ImportStreamFromFile(f) {
+		if res.Error != nil {
+			t.Fatalf("Stream error: %v", res.Error)
+		}
+		count++
+	}
+	if count != 1 {
+		t.Errorf("Expected 1 row, got %d", count)
+	}
+}
+
+func TestExcelImporter_OnEvent(t *testing.T) {
+	filename := "test_import_events.xlsx"
+	f := excelize.NewFile()
+	_ = f.SetSheetRow("Sheet1", "A1", &[]string{"用户编号", "日期"})
+	_ = f.SetSheetRow("Sheet1", "A2", &[]string{"C1", "2023-10-01"})
+	_ = f.SetSheetRow("Sheet1", "A3", &[]string{"C2", "not a date"})
+	_ = f.SetSheetRow("Sheet1", "A4", &[]string{"C3", "2023-10-03"})
+	if err := f.SaveAs(filename); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(filename)
+
+	var events []Event
+	importer := NewExcelImporter(&ExcelImportConfig[TestRow]{
+		ProgressInterval: 2,
+		OnEvent:          func(ev Event) { events = append(events, ev) },
+		Validators: map[string]func(any) error{
+			"Date": func(v any) error {
+				if _, err := time.Parse("2006-01-02", v.(string)); err != nil {
+					return err
+				}
+				return nil
+			},
+		},
+	})
+
+	for range importer.ImportStreamLocal(filename) {
+	}
+
+	var kinds []string
+	for _, ev := range events {
+		kinds = append(kinds, ev.Kind.String())
+	}
+	want := "[file_opened header_resolved row_error rows_processed completed]"
+	if fmt.Sprint(kinds) != want {
+		t.Fatalf("Expected events %s, got %v", want, kinds)
+	}
+	if len(events[1].Columns) != 2 {
+		t.Errorf("Expected header columns on header event, got %v", events[1].Columns)
+	}
+	if events[2].RowIndex != 3 || events[2].Err == nil {
+		t.Errorf("Unexpected row error event: %+v", events[2])
+	}
+	last := events[len(events)-1]
+	if last.Rows != 3 || last.Errors != 1 || last.Err != nil {
+		t.Errorf("Unexpected completion event: %+v", last)
+	}
+
+	events = nil
+	if _, err := importer.ImportLocal(filename); err == nil {
+		t.Fatal("Expected batch import to fail on the bad row")
+	}
+	last = events[len(events)-1]
+	if last.Kind != EventCompleted || last.Err == nil {
+		t.Errorf("Expected aborted completion event, got %+v", last)
+	}
+}
+
+func TestExcelImporter_DynamicSlice(t *testing.T) {
+	type Point struct {
+		Key   string
+		Value float64
+	}
+	type OrderedRow struct {
+		ClientAccount string  `excel:"用户编号"`
+		Points        []Point `excel:"extra"`
+	}
+
+	rows := [][]string{
+		{"用户编号", "00:30", "01:00", "01:30", "02:00"},
+		{"C1", "1.5", "", "x", "4"},
+	}
+	data, err := NewExcelImporter(&ExcelImportConfig[OrderedRow]{}).importRows(rows)
+	if err != nil {
+		t.Fatalf("importRows failed: %v", err)
+	}
+	if fmt.Sprint(data[0].Points) != "[{00:30 1.5} {02:00 4}]" {
+		t.Errorf("Unexpected points: %v", data[0].Points)
+	}
+}
+
+func TestExcelImporter_ImportStreamContextCancel(t *testing.T) {
+	filename := "test_import_stream_cancel.xlsx"
+	f := excelize.NewFile()
+	_ = f.SetSheetRow("Sheet1", "A1", &[]string{"用户编号", "日期"})
+	for i := 2; i <= 50; i++ {
+		cell, _ := excelize.CoordinatesToCellName(1, i)
+		_ = f.SetSheetRow("Sheet1", cell, &[]string{fmt.Sprintf("C%d", i), "2023-10-01"})
+	}
+	if err := f.SaveAs(filename); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(filename)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := NewExcelImporter(&ExcelImportConfig[TestRow]{}).ImportStreamLocalContext(ctx, filename)
+	if res, ok := <-ch; !ok || res.Error != nil {
+		t.Fatalf("Expected a first row before cancelling, got %+v", res)
+	}
+	cancel()
+
+	// A producer already waiting to send may deliver one more row, then it must close the channel
+	remaining := 0
+	for range ch {
+		remaining++
+	}
+	if remaining > 1 {
+		t.Errorf("Expected producer to stop after cancel, got %d more rows", remaining)
+	}
+}
+
+func TestExcelImporter_PreTransforms(t *testing.T) {
+	type StatusRow struct {
+		Code   string `excel:"编号"`
+		Status int    `excel:"状态"`
+	}
+	synonyms := map[string]string{"启用": "1", "停用": "0", "N/A": ""}
+
+	rows := [][]string{
+		{"编号", "状态"},
+		{"sku-001", "启用"},
+		{"sku-002", "N/A"},
+	}
+	data, err := NewExcelImporter(&ExcelImportConfig[StatusRow]{
+		DefaultValues: map[string]any{"Status": 9},
+		PreTransforms: map[string]func(string) string{
+			"Code":   func(s string) string { return strings.ToUpper(strings.TrimPrefix(s, "sku-")) },
+			"Status": func(s string) string { return synonyms[s] },
+		},
+	}).importRows(rows)
+	if err != nil {
+		t.Fatalf("importRows failed: %v", err)
+	}
+	if fmt.Sprint(data) != "[{001 1} {002 9}]" {
+		t.Errorf("Unexpected transformed rows: %v", data)
+	}
+}
+
+func TestExcelImporter_TrimSets(t *testing.T) {
+	type PriceRow struct {
+		Code  string   `excel:"编号"`
+		Price float64  `excel:"价格,trimset:$¥€"`
+		Cost  *float64 `excel:"成本"`
+	}
+
+	rows := [][]string{
+		{"编号", "价格", "成本"},
+		{"'0012", "¥ 12.50", "$"},
+	}
+	data, err := NewExcelImporter(&ExcelImportConfig[PriceRow]{
+		TrimSets: map[string]string{"Code": "'", "Cost": "$"},
+	}).importRows(rows)
+	if err != nil {
+		t.Fatalf("importRows failed: %v", err)
+	}
+	if len(data) != 1 || data[0].Code != "0012" || data[0].Price != 12.5 || data[0].Cost != nil {
+		t.Errorf("Unexpected trimmed rows: %+v", data)
+	}
+}
+
+func TestExcelImporter_NullValues(t *testing.T) {
+	type MeterRow struct {
+		Code    string            `excel:"编号"`
+		Reading *float64          `excel:"读数"`
+		Status  int               `excel:"状态"`
+		Extra   map[string]string `excel:"extra"`
+	}
+
+	rows := [][]string{
+		{"编号", "读数", "状态", "备注"},
+		{"m1", "null", "#N/A", "-"},
+		{"m2", "1.5", "2", "ok"},
+	}
+	data, err := NewExcelImporter(&ExcelImportConfig[MeterRow]{
+		NullValues:    []string{"NULL", "N/A", "#N/A", "-"},
+		DefaultValues: map[string]any{"Status": 9},
+	}).importRows(rows)
+	if err != nil {
+		t.Fatalf("importRows failed: %v", err)
+	}
+	if data[0].Reading != nil || data[0].Status != 9 || len(data[0].Extra) != 0 {
+		t.Errorf("Expected null cells treated as empty, got %+v", data[0])
+	}
+	if data[1].Reading == nil || *data[1].Reading != 1.5 || data[1].Extra["备注"] != "ok" {
+		t.Errorf("Unexpected regular row: %+v", data[1])
+	}
+}
+
+func TestExcelImporter_RowConverters(t *testing.T) {
+	type PaymentRow struct {
+		Amount int64  `excel:"金额"`
+		Note   string `excel:"备注"`
+	}
+	rates := map[string]int64{"CNY": 1, "USD": 7}
+
+	imp := NewExcelImporter(&ExcelImportConfig[PaymentRow]{
+		RowConverters: map[string]RowConverter{
+			"Amount": func(cell string, row []string, cols map[string]int) (any, error) {
+				rate, ok := rates[row[cols["币种"]]]
+				if !ok {
+					return nil, fmt.Errorf("unknown currency %s", row[cols["币种"]])
+				}
+				amount, err := strconv.ParseInt(cell, 10, 64)
+				return amount * rate, err
+			},
+		},
+	})
+
+	data, err := imp.importRows([][]string{
+		{"金额", "币种", "备注"},
+		{"10", "USD", "a"},
+		{"10", "CNY", "b"},
+	})
+	if err != nil {
+		t.Fatalf("importRows failed: %v", err)
+	}
+	if fmt.Sprint(data) != "[{70 a} {10 b}]" {
+		t.Errorf("Unexpected converted rows: %v", data)
+	}
+
+	_, err = imp.importRows([][]string{{"金额", "币种", "备注"}, {"10", "EUR", "c"}})
+	if err == nil || !strings.Contains(err.Error(), "unknown currency EUR") {
+		t.Errorf("Expected currency error, got %v", err)
+	}
+
+	// Sibling cells are sanitized even though only mapped columns are projected otherwise
+	imp.config.SanitizeCells = true
+	data, err = imp.importRows([][]string{{"金额", "币种", "备注"}, {"10", "USD\u200b", "d"}})
+	if err != nil || fmt.Sprint(data) != "[{70 d}]" {
+		t.Errorf("Expected sanitized sibling cells, got %v, %v", data, err)
+	}
+}
+
+func TestExcelImporter_HeaderAliases(t *testing.T) {
+	type AmountRow struct {
+		Code   string `excel:"Code"`
+		Amount int    `excel:"Amount"`
+	}
+
+	rows := [][]string{
+		{"Code", "Amuont*"},
+		{"a", "12"},
+	}
+	data, err := NewExcelImporter(&ExcelImportConfig[AmountRow]{
+		HeaderAliases: map[string]string{"Amuont": "Amount"},
+	}).importRows(rows)
+	if err != nil {
+		t.Fatalf("importRows failed: %v", err)
+	}
+	if len(data) != 1 || data[0].Amount != 12 {
+		t.Errorf("Expected aliased column to be mapped, got %+v", data)
+	}
+}
+
+func TestExcelImporter_DecimalSeparator(t *testing.T) {
+	type PriceRow struct {
+		Price float64            `excel:"Price"`
+		Qty   int                `excel:"Qty"`
+		Extra map[string]float64 `excel:"extra"`
+	}
+	parse := func(thousands string, cells ...string) ([]PriceRow, error) {
+		rows := [][]string{{"Price", "Qty", "Tax"}}
+		for i := 0; i < len(cells); i += 3 {
+			rows = append(rows, cells[i:i+3])
+		}
+		return NewExcelImporter(&ExcelImportConfig[PriceRow]{
+			DecimalSeparator:   ",",
+			ThousandsSeparator: thousands,
+		}).importRows(rows)
+	}
+
+	data, err := parse(".", "1.234,56", "1.000", "0,5", "-1.234.567,8", "-12", "-0,25")
+	if err != nil {
+		t.Fatalf("importRows failed: %v", err)
+	}
+	if data[0].Price != 1234.56 || data[0].Qty != 1000 || data[0].Extra["Tax"] != 0.5 {
+		t.Errorf("Unexpected first row: %+v", data[0])
+	}
+	if data[1].Price != -1234567.8 || data[1].Qty != -12 || data[1].Extra["Tax"] != -0.25 {
+		t.Errorf("Unexpected negative row: %+v", data[1])
+	}
+
+	data, err = parse(" ", "1 234,56", "2", "", "1\u00a0234\u00a0567,5", "3", "")
+	if err != nil {
+		t.Fatalf("importRows failed: %v", err)
+	}
+	if data[0].Price != 1234.56 || data[1].Price != 1234567.5 {
+		t.Errorf("Unexpected space-grouped prices: %+v", data)
+	}
+
+	if _, err := parse(".", "1.5", "1", ""); err == nil {
+		t.Error("Expected error for a malformed thousands group")
+	}
+
+	us, err := NewExcelImporter(&ExcelImportConfig[PriceRow]{}).importRows([][]string{{"Price", "Qty"}, {"1234.56", "-3"}})
+	if err != nil || us[0].Price != 1234.56 || us[0].Qty != -3 {
+		t.Errorf("Expected default parsing unchanged, got %+v %v", us, err)
+	}
+
+	// Cells stored as numbers already read as 1.234, only text is localized
+	f := excelize.NewFile()
+	defer f.Close()
+	_ = f.SetSheetRow("Sheet1", "A1", &[]string{"Price", "Qty", "Tax"})
+	_ = f.SetSheetRow("Sheet1", "A2", &[]any{1.234, "1.000", 0.5})
+	_ = f.SetSheetRow("Sheet1", "A3", &[]any{"1.234,5", 7, "0,25"})
+	localized := NewExcelImporter(&ExcelImportConfig[PriceRow]{DecimalSeparator: ",", ThousandsSeparator: "."})
+	data, err = localized.ImportFromFile(f)
+	if err != nil {
+		t.Fatalf("ImportFromFile failed: %v", err)
+	}
+	if data[0].Price != 1.234 || data[0].Qty != 1000 || data[0].Extra["Tax"] != 0.5 {
+		t.Errorf("Unexpected numeric cells: %+v", data[0])
+	}
+	if data[1].Price != 1234.5 || data[1].Qty != 7 || data[1].Extra["Tax"] != 0.25 {
+		t.Errorf("Unexpected text cells: %+v", data[1])
+	}
+	res := <-localized.ImportStreamFromFile(f)
+	if res.Error != nil || res.Data.Price != 1.234 {
+		t.Errorf("Expected streamed numeric cell unchanged, got %+v", res)
+	}
+}
+
+func TestExcelImporter_FloatEpsilon(t *testing.T) {
+	type ReadingRow struct {
+		Value float64            `excel:"Value"`
+		Extra map[string]float64 `excel:"extra"`
+	}
+	rows := [][]string{{"Value", "Drift"}, {"-0", "1e-9"}, {"-0.0000001", "-1.5e-12"}, {"2.5e-3", "0.25"}}
+
+	data, err := NewExcelImporter(&ExcelImportConfig[ReadingRow]{FloatEpsilon: 1e-6}).importRows(rows)
+	if err != nil {
+		t.Fatalf("importRows failed: %v", err)
+	}
+	for i, row := range data[:2] {
+		if row.Value != 0 || math.Signbit(row.Value) || row.Extra["Drift"] != 0 || math.Signbit(row.Extra["Drift"]) {
+			t.Errorf("Row %d: expected positive zeros, got %+v", i, row)
+		}
+	}
+	if data[2].Value != 2.5e-3 || data[2].Extra["Drift"] != 0.25 {
+		t.Errorf("Expected values above epsilon unchanged, got %+v", data[2])
+	}
+
+	data, err = NewExcelImporter(&ExcelImportConfig[ReadingRow]{}).importRows(rows)
+	if err != nil {
+		t.Fatalf("importRows failed: %v", err)
+	}
+	if math.Signbit(data[0].Value) || data[0].Extra["Drift"] != 1e-9 {
+		t.Errorf("Expected only negative zero normalized by default, got %+v", data[0])
+	}
+
+	if _, err := NewExcelImporter(&ExcelImportConfig[ReadingRow]{FloatEpsilon: -1}).importRows(rows); err == nil {
+		t.Error("Expected error for a negative FloatEpsilon")
+	}
+}
+
+func TestExcelImporter_LenientKinds(t *testing.T) {
+	type LooseRow struct {
+		Code  string     `excel:"Code"`
+		Any   any        `excel:"Any"`
+		Phase complex128 `excel:"Phase"`
+		Feed  chan int   `excel:"Feed"`
+	}
+	rows := [][]string{{"Code", "Any", "Phase", "Feed"}, {"7", "x", "1+2i", "f"}}
+	converters := map[string]func(string) (any, error){
+		"Code": func(cell string) (any, error) { return strconv.Atoi(cell) },
+	}
+
+	var warnings []string
+	data, err := NewExcelImporter(&ExcelImportConfig[LooseRow]{
+		CustomConverters: converters,
+		LenientKinds:     true,
+		OnWarning:        func(w Warning) { warnings = append(warnings, w.String()) },
+	}).importRows(rows)
+	if err != nil {
+		t.Fatalf("importRows failed: %v", err)
+	}
+	if row := data[0]; row.Code != "7" || row.Any != "x" || row.Phase != 0 || row.Feed != nil {
+		t.Errorf("Unexpected best-effort row: %+v", row)
+	}
+	want := []string{
+		"row 2 column Phase: field Phase left empty: unsupported kind: complex128",
+		"row 2 column Feed: field Feed left empty: unsupported kind: chan",
+	}
+	if !reflect.DeepEqual(warnings, want) {
+		t.Errorf("Expected warnings %q, got %q", want, warnings)
+	}
+
+	_, err = NewExcelImporter(&ExcelImportConfig[LooseRow]{CustomConverters: converters}).importRows(rows)
+	if !errors.Is(err, ErrUnsupportedKind) {
+		t.Errorf("Expected strict imports to fail with ErrUnsupportedKind, got %v", err)
+	}
+}
+
+func TestExcelImporter_TrailingCells(t *testing.T) {
+	type NameRow struct {
+		Code     string   `excel:"编号"`
+		Name     string   `excel:"名称"`
+		Trailing []string `excel:"trailing"`
+	}
+	rows := [][]string{
+		{"编号", "名称"},
+		{"1", "a", ""},
+		{"2", "\"Smith", " Jr.\"", "", "x"},
+	}
+
+	data, err := NewExcelImporter(&ExcelImportConfig[NameRow]{}).importRows(rows)
+	if err != nil || len(data) != 2 || data[1].Trailing != nil {
+		t.Fatalf("Expected trailing cells ignored by default, got %+v %v", data, err)
+	}
+
+	_, err = NewExcelImporter(&ExcelImportConfig[NameRow]{OnTrailingCells: TrailingCellError}).importRows(rows)
+	if err == nil || err.Error() != "row 3 error: row has 5 cells, header has 2 columns" {
+		t.Errorf("Expected trailing cell error on row 3, got %v", err)
+	}
+
+	data, err = NewExcelImporter(&ExcelImportConfig[NameRow]{OnTrailingCells: TrailingCellCapture}).importRows(rows)
+	if err != nil {
+		t.Fatalf("importRows failed: %v", err)
+	}
+	if data[0].Trailing != nil || fmt.Sprintf("%q", data[1].Trailing) != `[" Jr.\"" "" "x"]` {
+		t.Errorf("Unexpected captured cells: %q %q", data[0].Trailing, data[1].Trailing)
+	}
+}
+
+func TestExcelImporter_Percent100(t *testing.T) {
+	type RateRow struct {
+		Name  string   `excel:"名称"`
+		Rate  int      `excel:"比率,percent100"`
+		Share *float64 `excel:"占比,percent100"`
+	}
+
+	rows := [][]string{
+		{"名称", "比率", "占比"},
+		{"a", "0.45", "0.125"},
+		{"b", "7%", "50%"},
+	}
+	data, err := NewExcelImporter(&ExcelImportConfig[RateRow]{}).importRows(rows)
+	if err != nil {
+		t.Fatalf("importRows failed: %v", err)
+	}
+	if data[0].Rate != 45 || *data[0].Share != 12.5 {
+		t.Errorf("Unexpected fraction conversion: %d %v", data[0].Rate, *data[0].Share)
+	}
+	if data[1].Rate != 7 || *data[1].Share != 50 {
+		t.Errorf("Unexpected percent text conversion: %d %v", data[1].Rate, *data[1].Share)
+	}
+}
+
+func TestExcelImporter_Manifest(t *testing.T) {
+	type AuditRow struct {
+		Code  string            `excel:"编号"`
+		Rate  int               `excel:"比率,percent100,optional"`
+		Extra map[string]string `excel:"extra,pattern:^\\d"`
+	}
+	importer := NewExcelImporter(&ExcelImportConfig[AuditRow]{
+		DefaultValues: map[string]any{"Rate": 0},
+		Validators:    map[string]func(any) error{"Code": func(any) error { return nil }},
+		SkipRows:      map[int]bool{3: true},
+	})
+
+	data, err := json.Marshal(importer.Manifest())
+	if err != nil {
+		t.Fatalf("Marshal manifest failed: %v", err)
+	}
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		t.Fatalf("Unmarshal manifest failed: %v", err)
+	}
+
+	if len(m.Fields) != 2 || m.Fields[0].Column != "编号" || !m.Fields[0].Validator {
+		t.Fatalf("Unexpected fields: %+v", m.Fields)
+	}
+	if rate := m.Fields[1]; !rate.Percent100 || !rate.Optional || rate.Default == nil || rate.Type != "int" {
+		t.Errorf("Unexpected rate field: %+v", rate)
+	}
+	if m.DynamicField != "Extra" || m.DynamicPattern != `^\d` || m.OnMissingColumn != "error" || fmt.Sprint(m.SkipRows) != "[3]" {
+		t.Errorf("Unexpected manifest: %s", data)
+	}
+}
+
+func TestExcelImporter_CommentPrefix(t *testing.T) {
+	filename := "test_import_comments.xlsx"
+	f := excelize.NewFile()
+	_ = f.SetSheetRow("Sheet1", "A1", &[]string{"用户编号", "日期"})
+	_ = f.SetSheetRow("Sheet1", "A2", &[]string{"# exported by vendor tool"})
+	_ = f.SetSheetRow("Sheet1", "A3", &[]string{"C1", "2023-10-01"})
+	_ = f.SetSheetRow("Sheet1", "B4", &[]string{"  #note"})
+	_ = f.SetSheetRow("Sheet1", "A5", &[]string{"C2", "2023-10-02"})
+	if err := f.SaveAs(filename); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(filename)
+
+	importer := NewExcelImporter(&ExcelImportConfig[TestRow]{CommentPrefix: "#", MaxRows: 3})
+
+	data, err := importer.ImportLocal(filename)
+	if err != nil {
+		t.Fatalf("ImportLocal failed: %v", err)
+	}
+	if len(data) != 2 || data[1].ClientAccount != "C2" {
+		t.Errorf("Expected comment rows skipped, got %+v", data)
+	}
+
+	var streamed []string
+	for res := range importer.ImportStreamLocal(filename) {
+		if res.Error != nil {
+			t.Fatalf("Stream error: %v", res.Error)
+		}
+		streamed = append(streamed, res.Data.ClientAccount)
+	}
+	if fmt.Sprint(streamed) != "[C1 C2]" {
+		t.Errorf("Unexpected streamed rows: %v", streamed)
+	}
+}
+
+type LoginEvent struct {
+	User string `excel:"用户"`
+}
+
+type PurchaseEvent struct {
+	User   string  `excel:"用户"`
+	Amount float64 `excel:"金额"`
+}
+
+func TestExcelImporter_Discriminator(t *testing.T) {
+	rows := [][]string{
+		{"类型", "用户", "金额"},
+		{"login", "alice"},
+		{"purchase", "bob", "9.5"},
+		{"logout", "carol"},
+	}
+	importer := NewExcelImporter(&ExcelImportConfig[any]{
+		Discriminator: &Discriminator{
+			Column: "类型",
+			Variants: map[string]Variant{
+				"login":    VariantOf(&ExcelImportConfig[LoginEvent]{}),
+				"purchase": VariantOf(&ExcelImportConfig[PurchaseEvent]{}),
+			},
+		},
+	})
+
+	if _, err := importer.importRows(rows); err == nil || !strings.Contains(err.Error(), "logout") {
+		t.Errorf("Expected unknown variant error, got %v", err)
+	}
+
+	data, err := importer.importRows(rows[:3])
+	if err != nil {
+		t.Fatalf("importRows failed: %v", err)
+	}
+	if login, ok := data[0].(LoginEvent); !ok || login.User != "alice" {
+		t.Errorf("Expected LoginEvent, got %#v", data[0])
+	}
+	if purchase, ok := data[1].(PurchaseEvent); !ok || purchase.Amount != 9.5 {
+		t.Errorf("Expected PurchaseEvent, got %#v", data[1])
+	}
+
+	if _, err := importer.importRows([][]string{{"类型", "用户"}}); err == nil || !strings.Contains(err.Error(), "金额") {
+		t.Errorf("Expected missing variant column error, got %v", err)
+	}
+}
+
+func TestExcelImporter_RowLayoutConfig(t *testing.T) {
+	rows := [][]string{
+		{"标题"},
+		{"用户编号", "日期"},
+		{"C1", "2023-10-01"},
+	}
+
+	// HeaderRow alone moves the default StartRow along with it
+	data, err := NewExcelImporter(&ExcelImportConfig[TestRow]{HeaderRow: 2}).importRows(rows)
+	if err != nil {
+		t.Fatalf("importRows failed: %v", err)
+	}
+	if len(data) != 1 || data[0].ClientAccount != "C1" {
+		t.Errorf("Expected only the data row, got %+v", data)
+	}
+
+	for _, config := range []*ExcelImportConfig[TestRow]{
+		{HeaderRow: 2, StartRow: 2},
+		{HeaderRow: 3, StartRow: 2},
+		{HeaderRow: -1},
+	} {
+		importer := NewExcelImporter(config)
+		if _, err := importer.importRows(rows); err == nil || !strings.Contains(err.Error(), "invalid config") {
+			t.Errorf("HeaderRow %d StartRow %d: expected config error, got %v", config.HeaderRow, config.StartRow, err)
+		}
+		f := excelize.NewFile()
+		res := <-importer.ImportStreamFromFile(f)
+		if res.Error == nil || !strings.Contains(res.Error.Error(), "invalid config") {
+			t.Errorf("Expected config error from stream, got %v", res.Error)
+		}
+		f.Close()
+	}
+}
+
+func TestExcelImporter_BoolValues(t *testing.T) {
+	type FlagRow struct {
+		Name   string `excel:"名称"`
+		Active bool   `excel:"启用"`
+	}
+
+	rows := [][]string{{"名称", "启用"}, {"a", "是"}, {"b", "否"}, {"c", "Y"}, {"d", "no"}}
+	data, err := NewExcelImporter(&ExcelImportConfig[FlagRow]{}).importRows(rows)
+	if err != nil {
+		t.Fatalf("importRows failed: %v", err)
+	}
+	if fmt.Sprint(data) != "[{a true} {b false} {c true} {d false}]" {
+		t.Errorf("Unexpected bools: %v", data)
+	}
+
+	typo := [][]string{{"名称", "启用"}, {"a", "是的"}}
+	if _, err := NewExcelImporter(&ExcelImportConfig[FlagRow]{}).importRows(typo); err == nil || !strings.Contains(err.Error(), "invalid bool") {
+		t.Errorf("Expected invalid bool error, got %v", err)
+	}
+	data, err = NewExcelImporter(&ExcelImportConfig[FlagRow]{LenientBools: true}).importRows(typo)
+	if err != nil || data[0].Active {
+		t.Errorf("Expected lenient false, got %v %v", data, err)
+	}
+
+	custom := [][]string{{"名称", "启用"}, {"a", "开"}, {"b", "关"}}
+	data, err = NewExcelImporter(&ExcelImportConfig[FlagRow]{
+		BoolValues: map[string]bool{"开": true, "关": false},
+	}).importRows(custom)
+	if err != nil || !data[0].Active || data[1].Active {
+		t.Errorf("Unexpected custom bools: %v %v", data, err)
+	}
+
+	mixedCase := [][]string{{"名称", "启用"}, {"a", "on"}, {"b", "OFF"}}
+	data, err = NewExcelImporter(&ExcelImportConfig[FlagRow]{
+		BoolValues: map[string]bool{"On": true, "ON": true, "Off": false},
+	}).importRows(mixedCase)
+	if err != nil || !data[0].Active || data[1].Active {
+		t.Errorf("Unexpected case-insensitive bools: %v %v", data, err)
+	}
+
+	// Which of two conflicting texts wins would depend on map iteration order
+	_, err = NewExcelImporterE(&ExcelImportConfig[FlagRow]{
+		BoolValues: map[string]bool{"Y": true, "y": false},
+	})
+	if err == nil || !strings.Contains(err.Error(), `BoolValues "Y" and "y"`) {
+		t.Errorf("Expected conflicting BoolValues to be rejected, got %v", err)
+	}
+}
+
+// NarrowRow maps 5 of the 300 columns produced by wideSheetRows
+type NarrowRow struct {
+	ID    string  `excel:"C001"`
+	Name  string  `excel:"C050"`
+	Count int     `excel:"C100"`
+	Price float64 `excel:"C200"`
+	Note  string  `excel:"C300"`
+}
+
+func wideSheetRows(rowCount, colCount int) [][]string {
+	rows := make([][]string, rowCount+1)
+	rows[0] = make([]string, colCount)
+	for c := range rows[0] {
+		rows[0][c] = fmt.Sprintf("C%03d", c+1)
+	}
+	for r := 1; r <= rowCount; r++ {
+		rows[r] = make([]string, colCount)
+		for c := range rows[r] {
+			rows[r][c] = strconv.Itoa(r * c)
+		}
+	}
+	return rows
+}
+
+func BenchmarkImportRows_WideSheet(b *testing.B) {
+	rows := wideSheetRows(500, 300)
+	for _, sanitize := range []bool{false, true} {
+		b.Run(fmt.Sprintf("Sanitize=%v", sanitize), func(b *testing.B) {
+			importer := NewExcelImporter(&ExcelImportConfig[NarrowRow]{SanitizeCells: sanitize})
+			b.ReportAllocs()
+			for b.Loop() {
+				if _, err := importer.importRows(rows); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestExcelImporter_MissingColumnsError(t *testing.T) {
+	header := []string{"订单号", "金额"}
+	for i := 1; i <= 12; i++ {
+		header = append(header, fmt.Sprintf("备注%d", i))
+	}
+
+	_, err := NewExcelImporter(&ExcelImportConfig[TestRow]{}).importRows([][]string{header})
+	var missing *MissingColumnsError
+	if !errors.As(err, &missing) {
+		t.Fatalf("Expected MissingColumnsError, got %v", err)
+	}
+	if fmt.Sprint(missing.Missing) != "[日期 用户编号]" || len(missing.Found) != 14 || missing.Found[0] != "订单号" {
+		t.Errorf("Unexpected error fields: %+v", missing)
+	}
+	want := "missing columns: 日期, 用户编号 (found: 订单号, 金额, 备注1, 备注2, 备注3, 备注4, 备注5, 备注6, 备注7, 备注8 and 4 more)"
+	if err.Error() != want {
+		t.Errorf("Unexpected message: %s", err)
+	}
+}
+
+func TestExcelImporter_UnmergeValues(t *testing.T) {
+	type RegionRow struct {
+		Region string `excel:"区域"`
+		City   string `excel:"城市"`
+	}
+
+	filename := "test_import_merged.xlsx"
+	f := excelize.NewFile()
+	_ = f.SetSheetRow("Sheet1", "A1", &[]string{"区域", "城市"})
+	_ = f.SetSheetRow("Sheet1", "A2", &[]string{"华东", "上海"})
+	_ = f.SetCellValue("Sheet1", "B3", "杭州")
+	_ = f.SetCellValue("Sheet1", "B4", "南京")
+	_ = f.MergeCell("Sheet1", "A2", "A4")
+	_ = f.SetSheetRow("Sheet1", "A5", &[]string{"华北", "北京"})
+	_ = f.MergeCell("Sheet1", "A5", "A6")
+	if err := f.SaveAs(filename); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(filename)
+
+	importer := NewExcelImporter(&ExcelImportConfig[RegionRow]{UnmergeValues: true})
+	data, err := importer.ImportLocal(filename)
+	if err != nil {
+		t.Fatalf("ImportLocal failed: %v", err)
+	}
+	want := "[{华东 上海} {华东 杭州} {华东 南京} {华北 北京} {华北 }]"
+	if fmt.Sprint(data) != want {
+		t.Errorf("Expected %s, got %v", want, data)
+	}
+
+	var streamed []RegionRow
+	for res := range importer.ImportStreamLocal(filename) {
+		if res.Error != nil {
+			t.Fatalf("Stream error: %v", res.Error)
+		}
+		streamed = append(streamed, res.Data)
+	}
+	if fmt.Sprint(streamed) != want {
+		t.Errorf("Expected streamed %s, got %v", want, streamed)
+	}
+
+	// A merge over most of the sheet is cut short by the limits instead of expanded
+	small := excelize.NewFile()
+	_ = small.SetSheetRow("Sheet1", "A1", &[]string{"区域", "城市"})
+	_ = small.SetCellValue("Sheet1", "A2", "华东")
+	_ = small.MergeCell("Sheet1", "A2", "B3")
+	// MergeCell writes every covered cell, so the range is widened in the XML
+	huge := rewriteSheetXML(t, small, `ref="A2:B3"`, `ref="A2:CV200000"`)
+	defer huge.Close()
+	limited := NewExcelImporter(&ExcelImportConfig[RegionRow]{UnmergeValues: true, MaxRows: 100})
+	if _, err := limited.ImportFromFile(huge); err == nil || !strings.Contains(err.Error(), "exceeds max rows 100") {
+		t.Errorf("Expected max rows error, got %v", err)
+	}
+	var last ImportResult[RegionRow]
+	for res := range limited.ImportStreamFromFile(huge) {
+		last = res
+	}
+	if last.Error == nil || !strings.Contains(last.Error.Error(), "exceeds max rows 100") {
+		t.Errorf("Expected streamed max rows error, got %+v", last)
+	}
+	narrow := NewExcelImporter(&ExcelImportConfig[RegionRow]{UnmergeValues: true, MaxColumns: 5})
+	if _, err := narrow.ImportFromFile(huge); err == nil || !strings.Contains(err.Error(), "row has 6 columns, exceeds max 5") {
+		t.Errorf("Expected max columns error, got %v", err)
+	}
+}
+
+// rewriteSheetXML saves f and reopens it with one replacement made in the first
+// sheet's XML, to build files excelize itself would not write
+func rewriteSheetXML(t *testing.T, f *excelize.File, old, replacement string) *excelize.File {
+	t.Helper()
+	buf, err := f.WriteToBuffer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	zw := zip.NewWriter(&out)
+	for _, entry := range zr.File {
+		rc, err := entry.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := io.ReadAll(rc)
+		_ = rc.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if entry.Name == "xl/worksheets/sheet1.xml" {
+			data = bytes.Replace(data, []byte(old), []byte(replacement), 1)
+		}
+		w, err := zw.Create(entry.Name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, _ = w.Write(data)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	rewritten, err := excelize.OpenReader(&out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return rewritten
+}
+
+func TestNewExcelImporterE(t *testing.T) {
+	if _, err := NewExcelImporterE(&ExcelImportConfig[TestRow]{}); err != nil {
+		t.Errorf("Expected valid config, got %v", err)
+	}
+
+	_, err := NewExcelImporterE(&ExcelImportConfig[TestRow]{
+		Validators:       map[string]func(any) error{"Dat": func(any) error { return nil }},
+		CustomConverters: map[string]func(string) (any, error){"Client": nil},
+	})
+	if err == nil || !strings.Contains(err.Error(), "Validators references unknown field Dat") ||
+		!strings.Contains(err.Error(), "CustomConverters references unknown field Client") {
+		t.Errorf("Expected unknown field errors, got %v", err)
+	}
+
+	if _, err := NewExcelImporterE(&ExcelImportConfig[TestRow]{HeaderRow: 3, StartRow: 3}); err == nil {
+		t.Error("Expected row layout error")
+	}
+	if _, err := NewExcelImporterE(&ExcelImportConfig[string]{}); err == nil {
+		t.Error("Expected non-struct error")
+	}
+
+	type BadDynamic struct {
+		Name  string   `excel:"名称"`
+		Extra []string `excel:"extra"`
+	}
+	if _, err := NewExcelImporterE(&ExcelImportConfig[BadDynamic]{}); err == nil || !strings.Contains(err.Error(), "dynamic field Extra") {
+		t.Errorf("Expected dynamic field error, got %v", err)
+	}
+}
+
+func TestExcelImporter_DynamicStructValues(t *testing.T) {
+	type CellDetail struct {
+		Raw    string
+		Parsed float64
+	}
+	type DetailRow struct {
+		ClientAccount string                `excel:"用户编号"`
+		Slots         map[string]CellDetail `excel:"extra"`
+	}
+
+	rows := [][]string{
+		{"用户编号", "00:30", "01:00"},
+		{"C1", "1.5", "n/a"},
+	}
+	data, err := NewExcelImporter(&ExcelImportConfig[DetailRow]{}).importRows(rows)
+	if err != nil {
+		t.Fatalf("importRows failed: %v", err)
+	}
+	if got := data[0].Slots["00:30"]; got.Raw != "1.5" || got.Parsed != 1.5 {
+		t.Errorf("Unexpected parsed slot: %+v", got)
+	}
+	if got := data[0].Slots["01:00"]; got.Raw != "n/a" || got.Parsed != 0 {
+		t.Errorf("Expected raw text kept for unparsable slot, got %+v", got)
+	}
+
+	type Scaled struct{ Kw float64 }
+	type ScaledRow struct {
+		ClientAccount string            `excel:"用户编号"`
+		Slots         map[string]Scaled `excel:"extra"`
+	}
+	scaled, err := NewExcelImporter(&ExcelImportConfig[ScaledRow]{
+		CustomConverters: map[string]func(string) (any, error){
+			"Slots": func(s string) (any, error) {
+				f, err := strconv.ParseFloat(s, 64)
+				return Scaled{Kw: f / 1000}, err
+			},
+		},
+	}).importRows([][]string{{"用户编号", "00:30"}, {"C1", "1500"}})
+	if err != nil {
+		t.Fatalf("importRows failed: %v", err)
+	}
+	if scaled[0].Slots["00:30"].Kw != 1.5 {
+		t.Errorf("Expected converter result, got %+v", scaled[0].Slots)
+	}
+}
+
+func TestExcelImporter_RetryPolicy(t *testing.T) {
+	f := excelize.NewFile()
+	_ = f.SetSheetRow("Sheet1", "A1", &[]string{"用户编号", "日期"})
+	_ = f.SetSheetRow("Sheet1", "A2", &[]string{"C1", "2023-10-01"})
+	var content bytes.Buffer
+	if err := f.Write(&content); err != nil {
+		t.Fatal(err)
+	}
+
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write(content.Bytes())
+	}))
+	defer server.Close()
+
+	policy := &RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond}
+	data, err := NewExcelImporter(&ExcelImportConfig[TestRow]{Retry: policy}).Import(server.URL)
+	if err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+	if len(data) != 1 || attempts != 3 {
+		t.Errorf("Expected success on third attempt, got %d rows after %d attempts", len(data), attempts)
+	}
+
+	attempts = 0
+	policy.RetryableStatus = []int{http.StatusBadGateway}
+	if _, err := NewExcelImporter(&ExcelImportConfig[TestRow]{Retry: policy}).Import(server.URL); err == nil || attempts != 1 {
+		t.Errorf("Expected no retry for non-retryable status, got %v after %d attempts", err, attempts)
+	}
+
+	// The last attempt's error stays reachable once the retries run out
+	unavailable := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer unavailable.Close()
+	policy.RetryableStatus = nil
+	_, err = NewExcelImporter(&ExcelImportConfig[TestRow]{Retry: policy}).Import(unavailable.URL)
+	var status *statusError
+	if !errors.As(err, &status) || status.code != http.StatusServiceUnavailable || !strings.Contains(err.Error(), "after 3 attempts") {
+		t.Errorf("Expected a wrapped 503 status error after the retries, got %v", err)
+	}
+
+	// A refused connection is retried, a malformed URL is not
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+	if _, err := NewExcelImporter(&ExcelImportConfig[TestRow]{Retry: policy}).Import(closed.URL); err == nil || !strings.Contains(err.Error(), "after 3 attempts") {
+		t.Errorf("Expected a refused connection to be retried, got %v", err)
+	}
+	if _, err := NewExcelImporter(&ExcelImportConfig[TestRow]{Retry: policy}).Import("ftp://example.com/data.xlsx"); err == nil || strings.Contains(err.Error(), "attempts") {
+		t.Errorf("Expected an unsupported scheme to fail without retries, got %v", err)
+	}
+}
+
+func TestExcelImporter_SheetNotFound(t *testing.T) {
+	f := excelize.NewFile()
+	defer f.Close()
+	_, _ = f.NewSheet("Data")
+
+	imp := NewExcelImporter(&ExcelImportConfig[TestRow]{SheetName: "Sheet 1"})
+	_, err := imp.ImportFromFile(f)
+	var notFound *SheetNotFoundError
+	if !errors.As(err, &notFound) {
+		t.Fatalf("Expected SheetNotFoundError, got %v", err)
+	}
+	if notFound.Sheet != "Sheet 1" || fmt.Sprint(notFound.Available) != "[Sheet1 Data]" {
+		t.Errorf("Unexpected error details: %+v", notFound)
+	}
+	if err.Error() != "sheet Sheet 1 does not exist (available: Sheet1, Data)" {
+		t.Errorf("Unexpected message: %v", err)
+	}
+
+	res := <-imp.ImportStreamFromFile(f)
+	if !errors.As(res.Error, &notFound) {
+		t.Errorf("Expected SheetNotFoundError from stream, got %v", res.Error)
+	}
+}
+
+func TestExcelImporter_StartColumn(t *testing.T) {
+	f := excelize.NewFile()
+	defer f.Close()
+	sheet := [][]any{
+		{"Vendor report"},
+		{"", "", "用户编号", "日期", "00:15"},
+		{"合计", "x", "C1", "2023-10-01", "1.5"},
+		{"备注", "", "C2", "2023-10-02"},
+	}
+	for i, row := range sheet {
+		cell, _ := excelize.CoordinatesToCellName(1, i+1)
+		_ = f.SetSheetRow("Sheet1", cell, &row)
+	}
+	config := func() *ExcelImportConfig[TestRow] {
+		return &ExcelImportConfig[TestRow]{HeaderRow: 2, StartColumn: 3}
+	}
+
+	data, err := NewExcelImporter(config()).ImportFromFile(f)
+	if err != nil {
+		t.Fatalf("ImportFromFile failed: %v", err)
+	}
+	if len(data) != 2 || data[0].ClientAccount != "C1" || data[1].Date != "2023-10-02" {
+		t.Fatalf("Unexpected rows: %+v", data)
+	}
+	if fmt.Sprint(data[0].TimeData) != "map[00:15:1.5]" || len(data[1].TimeData) != 0 {
+		t.Errorf("Expected sidebar cells kept out of the dynamic field, got %v %v", data[0].TimeData, data[1].TimeData)
+	}
+
+	var streamed []TestRow
+	for res := range NewExcelImporter(config()).ImportStreamFromFile(f) {
+		if res.Error != nil {
+			t.Fatalf("Stream row %d failed: %v", res.RowIndex, res.Error)
+		}
+		streamed = append(streamed, res.Data)
+	}
+	if !reflect.DeepEqual(streamed, data) {
+		t.Errorf("Stream and batch import differ: %+v vs %+v", streamed, data)
+	}
+}
+
+func TestExcelImporter_NotSpreadsheet(t *testing.T) {
+	imp := NewExcelImporter(&ExcelImportConfig[TestRow]{})
+
+	var notSpreadsheet *NotSpreadsheetError
+	_, err := imp.ImportReader(strings.NewReader("<!DOCTYPE html><html><body>Login</body></html>"))
+	if !errors.As(err, &notSpreadsheet) || !strings.HasPrefix(notSpreadsheet.ContentType, "text/html") {
+		t.Errorf("Expected NotSpreadsheetError for HTML, got %v", err)
+	}
+	_, err = imp.ImportReader(strings.NewReader("%PDF-1.7\n"))
+	if !errors.As(err, &notSpreadsheet) || notSpreadsheet.ContentType != "application/pdf" {
+		t.Errorf("Expected NotSpreadsheetError for PDF, got %v", err)
+	}
+
+	var corrupt *CorruptFileError
+	_, err = imp.ImportReader(strings.NewReader("PK\x03\x04 truncated"))
+	if !errors.As(err, &corrupt) {
+		t.Errorf("Expected CorruptFileError for a truncated zip, got %v", err)
+	}
+
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = w.Write([]byte("<html>Please sign in</html>"))
+	}))
+	defer server.Close()
+
+	imp = NewExcelImporter(&ExcelImportConfig[TestRow]{Retry: &RetryPolicy{MaxAttempts: 3}})
+	_, err = imp.Import(server.URL)
+	if err == nil || !strings.Contains(err.Error(), "expected xlsx but got text/html") || attempts != 1 {
+		t.Errorf("Expected login page rejected without retries, got %v after %d attempts", err, attempts)
+	}
+}
+
+func TestExcelImporter_RecalcFormulas(t *testing.T) {
+	type LineRow struct {
+		Qty   int     `excel:"数量"`
+		Price float64 `excel:"单价"`
+		Total float64 `excel:"金额"`
+	}
+	f := excelize.NewFile()
+	defer f.Close()
+	_ = f.SetSheetRow("Sheet1", "A1", &[]string{"数量", "单价", "金额"})
+	_ = f.SetSheetRow("Sheet1", "A2", &[]any{3, 2.5})
+	// Formulas written without a cached result, as some tools do
+	_ = f.SetCellFormula("Sheet1", "C2", "A2*B2")
+
+	data, err := NewExcelImporter(&ExcelImportConfig[LineRow]{}).ImportFromFile(f)
+	if err != nil {
+		t.Fatalf("ImportFromFile failed: %v", err)
+	}
+	if data[0].Total != 0 {
+		t.Fatalf("Expected no cached result, got %v", data[0].Total)
+	}
+
+	data, err = NewExcelImporter(&ExcelImportConfig[LineRow]{RecalcFormulas: true}).ImportFromFile(f)
+	if err != nil {
+		t.Fatalf("ImportFromFile failed: %v", err)
+	}
+	if data[0].Total != 7.5 {
+		t.Errorf("Expected recalculated total 7.5, got %v", data[0].Total)
+	}
+
+	res := <-NewExcelImporter(&ExcelImportConfig[LineRow]{RecalcFormulas: true}).ImportStreamFromFile(f)
+	if res.Error != nil || res.Data.Total != 7.5 {
+		t.Errorf("Expected recalculated total from stream, got %+v", res)
+	}
+
+	// Only stored cells are recalculated, whatever the dimension claims
+	_ = f.SetSheetDimension("Sheet1", "A1:XFD1048576")
+	data, err = NewExcelImporter(&ExcelImportConfig[LineRow]{RecalcFormulas: true, MaxRows: 10}).ImportFromFile(f)
+	if err != nil || data[0].Total != 7.5 {
+		t.Errorf("Expected recalculated total with a forged dimension, got %v, %v", data, err)
+	}
+}
+
+func TestExcelImporter_ColumnGroups(t *testing.T) {
+	type RevenueRow struct {
+		Region  string            `excel:"Region"`
+		Revenue []float64         `excel:"Rev,pattern:^Q[1-4]_Rev$"`
+		Notes   []string          `excel:"Note,prefix:Note "`
+		Extra   map[string]string `excel:"extra"`
+	}
+
+	rows := [][]string{
+		{"Region", "Q1_Rev", "Note 1", "Q2_Rev", "Q3_Rev", "Q4_Rev", "Owner"},
+		{"East", "1.5", "late", "2", "", "4", "amy"},
+	}
+	imp := NewExcelImporter(&ExcelImportConfig[RevenueRow]{})
+	data, err := imp.importRows(rows)
+	if err != nil {
+		t.Fatalf("importRows failed: %v", err)
+	}
+	if fmt.Sprint(data[0].Revenue) != "[1.5 2 0 4]" || fmt.Sprint(data[0].Notes) != "[late]" {
+		t.Errorf("Unexpected column groups: %v %v", data[0].Revenue, data[0].Notes)
+	}
+	if fmt.Sprint(data[0].Extra) != "map[Owner:amy]" {
+		t.Errorf("Expected grouped columns kept out of the dynamic field, got %v", data[0].Extra)
+	}
+
+	rows[1][4] = "n/a"
+	if _, err := imp.importRows(rows); err == nil || !strings.Contains(err.Error(), "column Q3_Rev") {
+		t.Errorf("Expected conversion error naming the column, got %v", err)
+	}
+
+	if m := imp.Manifest(); m.Fields[1].Pattern != "^Q[1-4]_Rev$" {
+		t.Errorf("Expected pattern in manifest, got %+v", m.Fields)
+	}
+}
+
+func TestExcelImporter_CombineColumns(t *testing.T) {
+	type PersonRow struct {
+		ID       string `excel:"ID"`
+		FullName string
+		Extra    map[string]string `excel:"extra"`
+	}
+
+	rows := [][]string{
+		{"ID", "FirstName", "LastName", "Team"},
+		{"1", "Ada", "Lovelace", "core"},
+		{"2", "Plato", "", "core"},
+	}
+	imp := NewExcelImporter(&ExcelImportConfig[PersonRow]{
+		CombineColumns: map[string]CombineSpec{"FullName": {Columns: []string{"FirstName", "LastName"}, Sep: " "}},
+	})
+	data, err := imp.importRows(rows)
+	if err != nil {
+		t.Fatalf("importRows failed: %v", err)
+	}
+	if len(data) != 2 || data[0].FullName != "Ada Lovelace" || data[1].FullName != "Plato" {
+		t.Errorf("Unexpected combined names: %+v", data)
+	}
+	if fmt.Sprint(data[0].Extra) != "map[Team:core]" {
+		t.Errorf("Expected combined columns kept out of the dynamic field, got %v", data[0].Extra)
+	}
+	if m := imp.Manifest(); len(m.Fields) != 2 || m.Fields[1].Column != "FirstName+LastName" || m.Fields[1].Separator != " " {
+		t.Errorf("Expected combined field in manifest, got %+v", m.Fields)
+	}
+
+	var missing *MissingColumnsError
+	_, err = imp.importRows([][]string{{"ID", "FirstName"}, {"1", "Ada"}})
+	if !errors.As(err, &missing) || fmt.Sprint(missing.Missing) != "[LastName]" {
+		t.Errorf("Expected missing LastName column, got %v", err)
+	}
+}
+
+func TestExcelImporter_RowCells(t *testing.T) {
+	type AccountRow struct {
+		ID     string `excel:"ID"`
+		Age    int
+		Score  float64
+		Active bool
+	}
+
+	rows := [][]string{
+		{"ID", "Age", "Score", "Active", "Note"},
+		{"1", " 42 ", "9.5", "是"},
+		{"2", "n/a", "", "maybe"},
+	}
+	var missing []bool
+	data, err := NewExcelImporter(&ExcelImportConfig[AccountRow]{
+		RowHook: func(a *AccountRow, row []string, cols map[string]int) error {
+			cells := NewRowCells(row, cols)
+			a.Age, _ = cells.Int("Age")
+			a.Score, _ = cells.Float("Score")
+			a.Active, _ = cells.Bool("Active")
+			_, noteOK := cells.String("Note")
+			_, unknownOK := cells.String("Unknown")
+			missing = append(missing, !noteOK && !unknownOK)
+			return nil
+		},
+	}).importRows(rows)
+	if err != nil {
+		t.Fatalf("importRows failed: %v", err)
+	}
+	if fmt.Sprint(data) != "[{1 42 9.5 true} {2 0 0 false}]" {
+		t.Errorf("Unexpected hook results: %v", data)
+	}
+	if fmt.Sprint(missing) != "[true true]" {
+		t.Errorf("Expected short rows and unknown headers to report false, got %v", missing)
+	}
+}
+
+func TestExcelImporter_KeyColumns(t *testing.T) {
+	type ForecastRow struct {
+		Region  string             `excel:"Region"`
+		Product string             `excel:"Product"`
+		Values  map[string]float64 `excel:"extra"`
+	}
+
+	rows := [][]string{
+		{"Region", "Product", "Unit", "2024-01", "Product", "Total"},
+		{"East", "A", "pcs", "10", "7", "17"},
+	}
+	data, err := NewExcelImporter(&ExcelImportConfig[ForecastRow]{KeyColumns: 3}).importRows(rows)
+	if err != nil {
+		t.Fatalf("importRows failed: %v", err)
+	}
+	if len(data) != 1 || data[0].Product != "A" || fmt.Sprint(data[0].Values) != "map[2024-01:10 Total:17]" || data[0].Region != "East" {
+		t.Errorf("Unexpected forecast rows: %+v", data)
+	}
+
+	if _, err := NewExcelImporterE(&ExcelImportConfig[TestRow]{KeyColumns: -1}); err == nil {
+		t.Error("Expected error for negative KeyColumns")
+	}
+}
+
+func TestExcelImporter_DynamicKeyColumn(t *testing.T) {
+	type ReadingRow struct {
+		Sensor   string             `excel:"Sensor"`
+		Readings map[string]float64 `excel:"extra,pattern:^2024-"`
+	}
+
+	rows := [][]string{
+		{"Sensor", "Date", "Unit", "Value"},
+		{"s1", "2024-01-01", "°C", "1.5"},
+		{"s1", "2024-01-02", "°C", ""},
+		{"s2", "2023-12-31", "°C", "3"},
+	}
+	data, err := NewExcelImporter(&ExcelImportConfig[ReadingRow]{
+		DynamicKeyColumn:   "Date",
+		DynamicValueColumn: "Value",
+	}).importRows(rows)
+	if err != nil {
+		t.Fatalf("importRows failed: %v", err)
+	}
+	if got := fmt.Sprint(data); got != "[{s1 map[2024-01-01:1.5]} {s1 map[]} {s2 map[]}]" {
+		t.Errorf("Unexpected long-format rows: %s", got)
+	}
+
+	_, err = NewExcelImporter(&ExcelImportConfig[ReadingRow]{
+		DynamicKeyColumn:   "Day",
+		DynamicValueColumn: "Value",
+	}).importRows(rows)
+	var missing *MissingColumnsError
+	if !errors.As(err, &missing) || fmt.Sprint(missing.Missing) != "[Day]" {
+		t.Errorf("Expected missing key column, got %v", err)
+	}
+
+	if _, err := NewExcelImporterE(&ExcelImportConfig[ReadingRow]{DynamicKeyColumn: "Date"}); err == nil {
+		t.Error("Expected error for DynamicKeyColumn without DynamicValueColumn")
+	}
+	type PlainRow struct {
+		Sensor string `excel:"Sensor"`
+	}
+	if _, err := NewExcelImporterE(&ExcelImportConfig[PlainRow]{DynamicKeyColumn: "Date", DynamicValueColumn: "Value"}); err == nil {
+		t.Error("Expected error for DynamicKeyColumn without a dynamic field")
+	}
+}
+
+func TestExcelImporter_ImportPartial(t *testing.T) {
+	filename := "test_import_partial.xlsx"
+	f := excelize.NewFile()
+	_ = f.SetSheetRow("Sheet1", "A1", &[]string{"名称", "数量"})
+	_ = f.SetSheetRow("Sheet1", "A2", &[]string{"a", "1"})
+	_ = f.SetSheetRow("Sheet1", "A3", &[]string{"b", "2"})
+	_ = f.SetSheetRow("Sheet1", "A4", &[]string{"c", "x"})
+	_ = f.SetSheetRow("Sheet1", "A5", &[]string{"d", "4"})
+	if err := f.SaveAs(filename); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(filename)
+
+	type ItemRow struct {
+		Name  string `excel:"名称"`
+		Count int    `excel:"数量"`
+	}
+	imp := NewExcelImporter(&ExcelImportConfig[ItemRow]{})
+	data, rowIndex, err := imp.ImportPartialLocal(filename)
+	if err == nil || rowIndex != 4 || fmt.Sprint(data) != "[{a 1} {b 2}]" {
+		t.Errorf("Expected two rows before the failing row 4, got %v, %d, %v", data, rowIndex, err)
+	}
+
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, rowIndex, err = imp.ImportPartialReader(bytes.NewReader(content))
+	if err == nil || rowIndex != 4 || fmt.Sprint(data) != "[{a 1} {b 2}]" {
+		t.Errorf("Expected the reader to stop at row 4 too, got %v, %d, %v", data, rowIndex, err)
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(content)
+	}))
+	defer server.Close()
+	data, rowIndex, err = imp.ImportPartial(server.URL)
+	if err == nil || rowIndex != 4 || fmt.Sprint(data) != "[{a 1} {b 2}]" {
+		t.Errorf("Expected the download to stop at row 4 too, got %v, %d, %v", data, rowIndex, err)
+	}
+
+	if data, err := imp.ImportLocal(filename); data != nil || err == nil || err.Error() != "row 4 error: field Count conversion failed: invalid integer: x" {
+		t.Errorf("Expected ImportLocal to return no rows, got %v, %v", data, err)
+	}
+}
+
+func TestExcelImporter_MaxErrors(t *testing.T) {
+	filename := "test_import_max_errors.xlsx"
+	f := excelize.NewFile()
+	_ = f.SetSheetRow("Sheet1", "A1", &[]string{"名称", "数量"})
+	for row := 2; row <= 20; row++ {
+		_ = f.SetSheetRow("Sheet1", fmt.Sprintf("A%d", row), &[]string{"x", "garbage"})
+	}
+	if err := f.SaveAs(filename); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(filename)
+
+	type ItemRow struct {
+		Name  string `excel:"名称"`
+		Count int    `excel:"数量"`
+	}
+	imp := NewExcelImporter(&ExcelImportConfig[ItemRow]{MaxErrors: 3})
+	errs := imp.ValidateLocal(filename)
+	if len(errs) != 4 || errs[2].RowIndex != 4 || !errors.Is(errs[3], ErrTooManyErrors) || errs[3].Error() != "too many errors (>3)" {
+		t.Errorf("Expected three row errors and a summary, got %v", errs)
+	}
+
+	c, err := imp.CursorLocal(filename)
+	if err != nil {
+		t.Fatalf("CursorLocal failed: %v", err)
+	}
+	defer c.Close()
+	rows := 0
+	for c.Next() {
+		rows++
+	}
+	if rows != 3 || !errors.Is(c.Err(), ErrTooManyErrors) {
+		t.Errorf("Expected the cursor to stop after three rows, got %d rows and %v", rows, c.Err())
+	}
+}
+
+func TestExcelImporter_FilterPredicate(t *testing.T) {
+	filename := "test_import_filter.xlsx"
+	f := excelize.NewFile()
+	_ = f.SetSheetRow("Sheet1", "A1", &[]string{"名称", "状态"})
+	_ = f.SetSheetRow("Sheet1", "A2", &[]string{"a", "Active"})
+	_ = f.SetSheetRow("Sheet1", "A3", &[]string{"b", "Inactive"})
+	_ = f.SetSheetRow("Sheet1", "A4", &[]string{"", "Active"})
+	_ = f.SetSheetRow("Sheet1", "A5", &[]string{"d", "Active"})
+	if err := f.SaveAs(filename); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(filename)
+
+	type StatusRow struct {
+		Name   string `excel:"名称"`
+		Status string `excel:"状态"`
+	}
+	config := &ExcelImportConfig[StatusRow]{
+		OnEmptyCell:     EmptyCellError,
+		FilterPredicate: func(r *StatusRow) bool { return r.Status == "Active" },
+	}
+	imp := NewExcelImporter(config)
+
+	var streamed []string
+	for res := range imp.ImportStreamLocal(filename) {
+		if res.Error != nil {
+			streamed = append(streamed, fmt.Sprintf("error@%d", res.RowIndex))
+			continue
+		}
+		streamed = append(streamed, res.Data.Name)
+	}
+	if fmt.Sprint(streamed) != "[a error@4 d]" {
+		t.Errorf("Expected filtered stream with the invalid row reported, got %v", streamed)
+	}
+
+	config.OnEmptyCell = EmptyCellDefault
+	data, err := NewExcelImporter(config).ImportLocal(filename)
+	if err != nil || fmt.Sprint(data) != "[{a Active} { Active} {d Active}]" {
+		t.Errorf("Expected only active rows, got %v, %v", data, err)
+	}
+}
+
+func TestExcelImporter_LookupNonStringTarget(t *testing.T) {
+	filename := "test_import_lookup_price.xlsx"
+	f := excelize.NewFile()
+	_ = f.SetSheetRow("Sheet1", "A1", &[]string{"ProductCode", "Qty"})
+	_ = f.SetSheetRow("Sheet1", "A2", &[]string{"P1\u200b", "3"})
+	_, _ = f.NewSheet("Products")
+	_ = f.SetSheetRow("Products", "A1", &[]string{"Code", "Price"})
+	_ = f.SetSheetRow("Products", "A2", &[]string{"P1", "42"})
+	if err := f.SaveAs(filename); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(filename)
+
+	// The key column is not mapped to a field of its own
+	type PriceRow struct {
+		Qty   string `excel:"Qty"`
+		Price int
+	}
+	data, err := NewExcelImporter(&ExcelImportConfig[PriceRow]{
+		SheetName:     "Sheet1",
+		SanitizeCells: true,
+		Lookups:       map[string]Lookup{"Price": {Column: "ProductCode", Sheet: "Products", KeyColumn: "Code", ValueColumn: "Price"}},
+	}).ImportLocal(filename)
+	if err != nil || fmt.Sprint(data) != "[{3 42}]" {
+		t.Errorf("Expected the sanitized key's price converted to int, got %v, %v", data, err)
+	}
+}
+
+func TestExcelImporter_LookupSheetSanitizedAndLimited(t *testing.T) {
+	type NameRow struct {
+		Code string `excel:"ProductCode"`
+		Name string
+	}
+	lookups := map[string]Lookup{"Name": {Column: "ProductCode", Sheet: "Products", KeyColumn: "Code", ValueColumn: "Name"}}
+
+	f := excelize.NewFile()
+	defer f.Close()
+	_ = f.SetSheetRow("Sheet1", "A1", &[]string{"ProductCode"})
+	_ = f.SetSheetRow("Sheet1", "A2", &[]string{"P1"})
+	_, _ = f.NewSheet("Products")
+	_ = f.SetSheetRow("Products", "A1", &[]string{"Code", "Name"})
+	_ = f.SetSheetRow("Products", "A2", &[]string{"\ufeffP1\u200b", "Widget\u200b"})
+
+	data, err := NewExcelImporter(&ExcelImportConfig[NameRow]{SanitizeCells: true, Lookups: lookups}).ImportFromFile(f)
+	if err != nil || fmt.Sprint(data) != "[{P1 Widget}]" {
+		t.Errorf("Expected the reference sheet sanitized like the data sheet, got %q, %v", data, err)
+	}
+
+	for i := 3; i <= 20; i++ {
+		cell, _ := excelize.CoordinatesToCellName(1, i)
+		_ = f.SetSheetRow("Products", cell, &[]string{fmt.Sprintf("P%d", i), "x"})
+	}
+	_, err = NewExcelImporter(&ExcelImportConfig[NameRow]{MaxRows: 10, Lookups: lookups}).ImportFromFile(f)
+	if err == nil || !strings.Contains(err.Error(), "lookup Name: sheet exceeds max rows 10") {
+		t.Errorf("Expected MaxRows to cover the reference sheet, got %v", err)
+	}
+
+	_ = f.SetSheetRow("Products", "A5", &[]string{"P5", strings.Repeat("x", 100)})
+	_, err = NewExcelImporter(&ExcelImportConfig[NameRow]{MaxCellLength: 50, Lookups: lookups}).ImportFromFile(f)
+	if err == nil || !strings.Contains(err.Error(), "row 5 error: cell in column 2 exceeds max length 50") {
+		t.Errorf("Expected MaxCellLength to cover the reference sheet, got %v", err)
+	}
+}
+
+func TestExcelImporter_CommentFields(t *testing.T) {
+	filename := "test_import_comments.xlsx"
+	f := excelize.NewFile()
+	_ = f.SetSheetRow("Sheet1", "A1", &[]string{"Item", "Status"})
+	_ = f.SetSheetRow("Sheet1", "A2", &[]string{"a", "approved"})
+	_ = f.SetSheetRow("Sheet1", "A3", &[]string{"b", "rejected"})
+	_ = f.AddComment("Sheet1", excelize.Comment{Cell: "B3", Author: "QA", Paragraph: []excelize.RichTextRun{
+		{Text: "QA:", Font: &excelize.Font{Bold: true}},
+		{Text: " price missing "},
+	}})
+	_ = f.AddComment("Sheet1", excelize.Comment{Cell: "A2", Text: "not a Status note"})
+	if err := f.SaveAs(filename); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(filename)
+
+	type ReviewRow struct {
+		Item       string `excel:"Item"`
+		Status     string `excel:"Status"`
+		StatusNote string `excel:"Status,comment"`
+	}
+	imp := NewExcelImporter(&ExcelImportConfig[ReviewRow]{})
+	data, err := imp.ImportLocal(filename)
+	if err != nil || fmt.Sprint(data) != "[{a approved } {b rejected QA: price missing}]" {
+		t.Errorf("Expected Status comments captured, got %v, %v", data, err)
+	}
+
+	c, err := imp.CursorLocal(filename)
+	if err != nil {
+		t.Fatalf("CursorLocal failed: %v", err)
+	}
+	defer c.Close()
+	for c.Next() {
+		if row, _ := c.Scan(); row.Item == "b" && row.StatusNote != "QA: price missing" {
+			t.Errorf("Expected cursor rows to read comments, got %+v", row)
+		}
+	}
+
+	if fm := imp.Manifest().Fields[2]; fm.Field != "StatusNote" || fm.Column != "Status" || !fm.Comment {
+		t.Errorf("Unexpected manifest entry: %+v", fm)
+	}
+
+	type BadRow struct {
+		Status string `excel:"Status"`
+		Note   int    `excel:"Status,comment"`
+	}
+	if _, err := NewExcelImporterE(&ExcelImportConfig[BadRow]{}); err == nil {
+		t.Error("Expected error for a non-string comment field")
+	}
+}
+
+func TestExcelImporter_Lookups(t *testing.T) {
+	filename := "test_import_lookups.xlsx"
+	f := excelize.NewFile()
+	_ = f.SetSheetRow("Sheet1", "A1", &[]string{"ProductCode", "Qty"})
+	_ = f.SetSheetRow("Sheet1", "A2", &[]string{"P1", "2"})
+	_ = f.SetSheetRow("Sheet1", "A3", &[]string{"P9", "1"})
+	_, _ = f.NewSheet("Products")
+	_ = f.SetSheetRow("Products", "A1", &[]string{"Code", "Name"})
+	_ = f.SetSheetRow("Products", "A2", &[]string{"P1", "Widget"})
+	_ = f.SetSheetRow("Products", "A3", &[]string{"P1", "Duplicate"})
+	if err := f.SaveAs(filename); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(filename)
+
+	type OrderRow struct {
+		Code string `excel:"ProductCode"`
+		Qty  int    `excel:"Qty"`
+		Name string
+	}
+	config := &ExcelImportConfig[OrderRow]{
+		SheetName:     "Sheet1",
+		Lookups:       map[string]Lookup{"Name": {Column: "ProductCode", Sheet: "Products", KeyColumn: "Code", ValueColumn: "Name"}},
+		DefaultValues: map[string]any{"Name": "unknown"},
+	}
+	imp := NewExcelImporter(config)
+	data, err := imp.ImportLocal(filename)
+	if err != nil || fmt.Sprint(data) != "[{P1 2 Widget} {P9 1 unknown}]" {
+		t.Errorf("Expected looked up names, got %v, %v", data, err)
+	}
+
+	c, err := imp.CursorLocal(filename)
+	if err != nil {
+		t.Fatalf("CursorLocal failed: %v", err)
+	}
+	defer c.Close()
+	if !c.Next() {
+		t.Fatalf("Expected a row, got %v", c.Err())
+	}
+	if row, _ := c.Scan(); row.Name != "Widget" {
+		t.Errorf("Expected cursor rows to use the lookup, got %+v", row)
+	}
+
+	config.Lookups["Name"] = Lookup{Column: "ProductCode", Sheet: "Products", KeyColumn: "SKU", ValueColumn: "Name"}
+	var missing *MissingColumnsError
+	if _, err := NewExcelImporter(config).ImportLocal(filename); !errors.As(err, &missing) || !strings.HasPrefix(err.Error(), "lookup Name: missing columns: SKU") {
+		t.Errorf("Expected missing reference column error, got %v", err)
+	}
+}
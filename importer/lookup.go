@@ -0,0 +1,133 @@
+package importer
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// Lookup fills a field from a reference table on another sheet of the same
+// workbook, e.g. a product name looked up by the row's product code. The table's
+// first row is its header; when a key repeats, its first row wins. Rows whose key
+// is not in the table are handled like an empty cell.
+type Lookup struct {
+	Column      string // Header of the data sheet column holding the key
+	Sheet       string // Reference sheet
+	KeyColumn   string // Header of the reference sheet's key column
+	ValueColumn string // Header of the reference sheet's column copied into the field
+}
+
+func (l Lookup) String() string {
+	return fmt.Sprintf("%s!%s->%s", l.Sheet, l.KeyColumn, l.ValueColumn)
+}
+
+// withLookups returns a copy of the importer holding the Lookups tables read
+// from f, so the tables are built once per import
+func (importer *ExcelImporter[T]) withLookups(f *excelize.File) (*ExcelImporter[T], error) {
+	if len(importer.config.Lookups) == 0 {
+		return importer, nil
+	}
+	if f == nil {
+		return nil, fmt.Errorf("lookups are not supported for legacy .xls files")
+	}
+
+	tables := make(map[string]map[string]string, len(importer.config.Lookups))
+	for _, fieldName := range slices.Sorted(maps.Keys(importer.config.Lookups)) {
+		table, err := importer.readLookupTable(f, importer.config.Lookups[fieldName])
+		if err != nil {
+			return nil, fmt.Errorf("lookup %s: %w", fieldName, err)
+		}
+		tables[fieldName] = table
+	}
+
+	call := *importer
+	call.lookupTables = tables
+	return &call, nil
+}
+
+// readLookupTable maps the reference sheet's trimmed keys to their values. The
+// sheet is read like a data sheet: cells are sanitized with SanitizeCells, and
+// MaxRows, MaxColumns and MaxCellLength stop the read at the first offending row.
+func (importer *ExcelImporter[T]) readLookupTable(f *excelize.File, lookup Lookup) (map[string]string, error) {
+	sheets := f.GetSheetList()
+	if !slices.Contains(sheets, lookup.Sheet) {
+		return nil, &SheetNotFoundError{Sheet: lookup.Sheet, Available: sheets}
+	}
+	rows, err := f.Rows(lookup.Sheet)
+	if err != nil {
+		return nil, fmt.Errorf("read sheet failed: %v", err)
+	}
+	iter := excelizeRows{Rows: rows, opts: importer.readOptions()}
+	defer iter.Close()
+
+	var columnIndexMap map[string]int
+	var keyIdx, valueIdx int
+	table := make(map[string]string)
+	for rowIndex := 1; iter.Next(); rowIndex++ {
+		row, err := iter.Columns()
+		if err != nil {
+			return nil, fmt.Errorf("read row %d failed: %v", rowIndex, err)
+		}
+		if len(row) == 0 && columnIndexMap != nil {
+			continue
+		}
+		if importer.config.MaxRows > 0 && rowIndex > importer.config.MaxRows {
+			return nil, fmt.Errorf("sheet exceeds max rows %d", importer.config.MaxRows)
+		}
+		row = importer.sanitizeRow(row)
+		if err := importer.checkRowLimits(row); err != nil {
+			return nil, fmt.Errorf("row %d error: %v", rowIndex, err)
+		}
+
+		if columnIndexMap == nil {
+			if columnIndexMap, err = lookupColumns(row, lookup); err != nil {
+				return nil, err
+			}
+			keyIdx, valueIdx = columnIndexMap[lookup.KeyColumn], columnIndexMap[lookup.ValueColumn]
+			continue
+		}
+		if keyIdx >= len(row) {
+			continue
+		}
+		key := strings.TrimSpace(row[keyIdx])
+		if _, exists := table[key]; exists || key == "" {
+			continue
+		}
+		if valueIdx < len(row) {
+			table[key] = row[valueIdx]
+		} else {
+			table[key] = ""
+		}
+	}
+	if err := iter.Error(); err != nil {
+		return nil, fmt.Errorf("read sheet failed: %v", err)
+	}
+	if columnIndexMap == nil {
+		if _, err := lookupColumns(nil, lookup); err != nil {
+			return nil, err
+		}
+	}
+	return table, nil
+}
+
+// lookupColumns indexes the reference sheet's header and checks it holds the
+// key and value columns
+func lookupColumns(header []string, lookup Lookup) (map[string]int, error) {
+	columnIndexMap := make(map[string]int)
+	for idx, cellValue := range header {
+		columnIndexMap[strings.TrimSpace(cellValue)] = idx
+	}
+	var missing []string
+	for _, excelCol := range []string{lookup.KeyColumn, lookup.ValueColumn} {
+		if _, exists := columnIndexMap[excelCol]; !exists {
+			missing = append(missing, excelCol)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, &MissingColumnsError{Missing: missing, Found: foundColumns(columnIndexMap)}
+	}
+	return columnIndexMap, nil
+}
@@ -0,0 +1,182 @@
+package importer
+
+import (
+	"maps"
+	"reflect"
+	"slices"
+	"sort"
+)
+
+// Manifest is a JSON-serializable description of how an importer interprets a
+// file, for keeping an audit record next to the imported data. Functions such as
+// converters and validators are listed by presence only.
+type Manifest struct {
+	SheetName          string            `json:"sheet_name,omitempty"`
+	HeaderRow          int               `json:"header_row"`
+	StartRow           int               `json:"start_row"`
+	StartColumn        int               `json:"start_column"`
+	KeyColumns         int               `json:"key_columns,omitempty"`
+	SkipRows           []int             `json:"skip_rows,omitempty"`
+	CommentPrefix      string            `json:"comment_prefix,omitempty"`
+	Fields             []FieldManifest   `json:"fields"`
+	HeaderAliases      map[string]string `json:"header_aliases,omitempty"`
+	DynamicField       string            `json:"dynamic_field,omitempty"`
+	DynamicPattern     string            `json:"dynamic_pattern,omitempty"`
+	DynamicKeyColumn   string            `json:"dynamic_key_column,omitempty"`
+	DynamicValueColumn string            `json:"dynamic_value_column,omitempty"`
+	RowNumField        string            `json:"rownum_field,omitempty"`
+	RawRowField        string            `json:"rawrow_field,omitempty"`
+	RawField           string            `json:"raw_field,omitempty"`
+	OnMissingColumn    string            `json:"on_missing_column"`
+	OnEmptyCell        string            `json:"on_empty_cell"`
+	OnTrailingCells    string            `json:"on_trailing_cells"`
+	TrailingField      string            `json:"trailing_field,omitempty"`
+	TimeLayouts        []string          `json:"time_layouts"`
+	Location           string            `json:"location"`
+	BoolValues         map[string]bool   `json:"bool_values"`
+	LenientBools       bool              `json:"lenient_bools,omitempty"`
+	LenientKinds       bool              `json:"lenient_kinds,omitempty"`
+	NullValues         []string          `json:"null_values,omitempty"`
+	DecimalSeparator   string            `json:"decimal_separator,omitempty"`
+	ThousandsSeparator string            `json:"thousands_separator,omitempty"`
+	FloatEpsilon       float64           `json:"float_epsilon,omitempty"`
+	SanitizeCells      bool              `json:"sanitize_cells,omitempty"`
+	RawCellValues      bool              `json:"raw_cell_values,omitempty"`
+	MaxRows            int               `json:"max_rows,omitempty"`
+	MaxErrors          int               `json:"max_errors,omitempty"`
+	MaxColumns         int               `json:"max_columns,omitempty"`
+	MaxCellLength      int               `json:"max_cell_length,omitempty"`
+	RowHook            bool              `json:"row_hook,omitempty"`
+	FilterPredicate    bool              `json:"filter_predicate,omitempty"`
+}
+
+// FieldManifest describes one mapped struct field
+type FieldManifest struct {
+	Field            string   `json:"field"`
+	Column           string   `json:"column"`
+	Pattern          string   `json:"pattern,omitempty"`   // Header pattern of a column group, which has no single column
+	Columns          []string `json:"columns,omitempty"`   // Source columns of a combined field, see CombineColumns
+	Separator        string   `json:"separator,omitempty"` // Placed between a combined field's cells
+	Lookup           string   `json:"lookup,omitempty"`    // Reference table as Sheet!KeyColumn->ValueColumn, see Lookups
+	Comment          bool     `json:"comment,omitempty"`   // Receives the cell comments of Column instead of its values, see CommentFields
+	Type             string   `json:"type"`
+	Optional         bool     `json:"optional,omitempty"`
+	TrimSet          string   `json:"trim_set,omitempty"`
+	PreTransform     bool     `json:"pre_transform,omitempty"`
+	Converter        bool     `json:"converter,omitempty"`
+	Validator        bool     `json:"validator,omitempty"`
+	Percent100       bool     `json:"percent100,omitempty"`
+	Default          any      `json:"default,omitempty"`
+	DefaultEmptyOnly bool     `json:"default_empty_only,omitempty"`
+}
+
+// Manifest describes the importer's active configuration
+func (importer *ExcelImporter[T]) Manifest() Manifest {
+	config := importer.config
+	m := Manifest{
+		SheetName:          config.SheetName,
+		HeaderRow:          config.HeaderRow,
+		StartRow:           config.StartRow,
+		StartColumn:        config.StartColumn,
+		KeyColumns:         config.KeyColumns,
+		CommentPrefix:      config.CommentPrefix,
+		HeaderAliases:      maps.Clone(config.HeaderAliases),
+		DynamicField:       importer.dynamicField,
+		DynamicKeyColumn:   config.DynamicKeyColumn,
+		DynamicValueColumn: config.DynamicValueColumn,
+		RowNumField:        config.RowNumField,
+		RawRowField:        config.RawRowField,
+		RawField:           config.RawField,
+		OnMissingColumn:    config.OnMissingColumn.String(),
+		OnEmptyCell:        config.OnEmptyCell.String(),
+		OnTrailingCells:    config.OnTrailingCells.String(),
+		TrailingField:      config.TrailingField,
+		TimeLayouts:        slices.Clone(timeLayouts),
+		Location:           "UTC",
+		BoolValues:         maps.Clone(importer.boolTexts),
+		LenientBools:       config.LenientBools,
+		LenientKinds:       config.LenientKinds,
+		NullValues:         slices.Clone(config.NullValues),
+		DecimalSeparator:   config.DecimalSeparator,
+		ThousandsSeparator: config.ThousandsSeparator,
+		FloatEpsilon:       config.FloatEpsilon,
+		SanitizeCells:      config.SanitizeCells,
+		RawCellValues:      config.RawCellValues,
+		MaxErrors:          config.MaxErrors,
+		MaxRows:            config.MaxRows,
+		MaxColumns:         config.MaxColumns,
+		MaxCellLength:      config.MaxCellLength,
+		RowHook:            config.RowHook != nil,
+		FilterPredicate:    config.FilterPredicate != nil,
+	}
+	if importer.dynamicFilter != nil {
+		m.DynamicPattern = importer.dynamicFilter.String()
+	}
+	if config.Location != nil {
+		m.Location = config.Location.String()
+	}
+	for row, skip := range config.SkipRows {
+		if skip {
+			m.SkipRows = append(m.SkipRows, row)
+		}
+	}
+	sort.Ints(m.SkipRows)
+
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return m
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		column := importer.findExcelColumnForField(field)
+		group := importer.columnGroups[field.Name]
+		spec, combined := config.CombineColumns[field.Name]
+		if combined {
+			column = spec.combineColumn()
+		}
+		lookup, hasLookup := config.Lookups[field.Name]
+		if hasLookup {
+			column = lookup.Column
+		}
+		commentColumn, isComment := config.CommentFields[field.Name]
+		if isComment {
+			column = commentColumn
+		}
+		if (column == "" && group == nil) || field.Name == importer.dynamicField {
+			continue
+		}
+
+		fm := FieldManifest{
+			Field:        field.Name,
+			Column:       column,
+			Type:         field.Type.String(),
+			Optional:     slices.Contains(config.OptionalColumns, column),
+			TrimSet:      config.TrimSets[field.Name],
+			PreTransform: config.PreTransforms[field.Name] != nil,
+			Converter:    config.CustomConverters[field.Name] != nil || config.RowConverters[field.Name] != nil,
+			Validator:    config.Validators[field.Name] != nil,
+			Percent100:   config.PercentFields[field.Name],
+			Comment:      isComment,
+		}
+		if combined {
+			fm.Columns, fm.Separator = slices.Clone(spec.Columns), spec.Sep
+		}
+		if hasLookup {
+			fm.Lookup = lookup.String()
+		}
+		if group != nil {
+			fm.Pattern = group.String()
+		}
+		if spec, ok := config.DefaultSpecs[field.Name]; ok {
+			fm.Default, fm.DefaultEmptyOnly = spec.Value, spec.EmptyOnly
+		} else if value, ok := config.DefaultValues[field.Name]; ok {
+			fm.Default = value
+		}
+		m.Fields = append(m.Fields, fm)
+	}
+	return m
+}
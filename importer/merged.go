@@ -0,0 +1,218 @@
+package importer
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// cellFills maps 1-based sheet rows to 0-based columns and the value replacing
+// what the sheet stores there: a merged range's anchor value for UnmergeValues,
+// or a recalculated formula result for RecalcFormulas. Merged ranges stay
+// ranges and are applied as rows go by, so rows must be filled in order.
+type cellFills struct {
+	cells   map[int]map[int]string
+	merges  []mergeFill // sorted by firstRow
+	next    int         // first merge not yet active
+	active  []mergeFill // merges covering the current row
+	lastRow int
+	maxRows int
+}
+
+// mergeFill is a merged range, with 0-based columns
+type mergeFill struct {
+	firstRow, lastRow int
+	firstCol, lastCol int
+	value             string
+}
+
+func (m *cellFills) set(row, col int, value string) {
+	if m.cells[row] == nil {
+		m.cells[row] = make(map[int]string)
+	}
+	m.cells[row][col] = value
+	m.lastRow = max(m.lastRow, row)
+}
+
+// extends reports whether row, past the stored rows, still holds covered cells.
+// Rows far enough past MaxRows to fail the sheet are never added.
+func (m *cellFills) extends(row, stored int) bool {
+	return row <= m.lastRow && (m.maxRows == 0 || row <= stored+m.maxRows+1)
+}
+
+// readFills collects the replaced cells of a sheet, nil when neither
+// UnmergeValues nor RecalcFormulas is set
+func (importer *ExcelImporter[T]) readFills(f *excelize.File, sheetName string) (*cellFills, error) {
+	if !importer.config.UnmergeValues && !importer.config.RecalcFormulas {
+		return nil, nil
+	}
+	fills := &cellFills{cells: make(map[int]map[int]string), maxRows: importer.config.MaxRows}
+	if importer.config.RecalcFormulas {
+		if err := importer.recalcFormulas(f, sheetName, fills); err != nil {
+			return nil, err
+		}
+	}
+	if importer.config.UnmergeValues {
+		if err := importer.readMergeFills(f, sheetName, fills); err != nil {
+			return nil, err
+		}
+	}
+	return fills, nil
+}
+
+// readMergeFills adds the merged ranges of a sheet, using recalculated anchor values when present.
+// Ranges are clipped to MaxColumns but not expanded into cells.
+func (importer *ExcelImporter[T]) readMergeFills(f *excelize.File, sheetName string, fills *cellFills) error {
+	merges, err := f.GetMergeCells(sheetName)
+	if err != nil {
+		return fmt.Errorf("read merged cells failed: %v", err)
+	}
+
+	for _, merge := range merges {
+		startCol, startRow, err := excelize.CellNameToCoordinates(merge.GetStartAxis())
+		if err != nil {
+			return err
+		}
+		endCol, endRow, err := excelize.CellNameToCoordinates(merge.GetEndAxis())
+		if err != nil {
+			return err
+		}
+		value, recalculated := fills.cells[startRow][startCol-1]
+		if !recalculated {
+			if value, err = f.GetCellValue(sheetName, merge.GetStartAxis(), importer.readOptions()); err != nil {
+				return fmt.Errorf("read merged cell %s failed: %v", merge.GetStartAxis(), err)
+			}
+		}
+		// Columns past MaxColumns fail the row whatever they hold, so one is enough
+		if importer.config.MaxColumns > 0 {
+			endCol = min(endCol, importer.config.StartColumn+importer.config.MaxColumns)
+		}
+		fills.merges = append(fills.merges, mergeFill{firstRow: startRow, lastRow: endRow, firstCol: startCol - 1, lastCol: endCol - 1, value: value})
+		fills.lastRow = max(fills.lastRow, endRow)
+	}
+	slices.SortFunc(fills.merges, func(a, b mergeFill) int { return a.firstRow - b.firstRow })
+	return nil
+}
+
+// recalcFormulas evaluates every formula cell of the sheet instead of trusting
+// its cached result. Formulas that fail to evaluate keep the cached value and
+// are reported through OnWarning. Only the stored cells are visited, and none
+// past where MaxRows or MaxColumns fail the sheet, whatever its dimension claims.
+func (importer *ExcelImporter[T]) recalcFormulas(f *excelize.File, sheetName string, fills *cellFills) error {
+	rows, err := f.Rows(sheetName)
+	if err != nil {
+		return fmt.Errorf("read sheet failed: %v", err)
+	}
+	defer rows.Close()
+
+	lastCol := 0
+	if importer.config.MaxColumns > 0 {
+		lastCol = importer.config.StartColumn - 1 + importer.config.MaxColumns
+	}
+	comments := 0
+	for row := 1; rows.Next(); row++ {
+		cells, err := rows.Columns(importer.readOptions())
+		if err != nil {
+			return fmt.Errorf("read row %d failed: %v", row, err)
+		}
+		if importer.isCommentRow(importer.sanitizeRow(importer.offsetRow(cells)), row) {
+			comments++
+		}
+		if importer.config.MaxRows > 0 && row-comments > importer.config.MaxRows {
+			break
+		}
+		if lastCol > 0 {
+			cells = cells[:min(len(cells), lastCol)]
+		}
+
+		// Formula cells are stored even without a cached value, so the row covers them all
+		for col := 1; col <= len(cells); col++ {
+			cell, _ := excelize.CoordinatesToCellName(col, row)
+			formula, err := f.GetCellFormula(sheetName, cell)
+			if err != nil {
+				return fmt.Errorf("read formula %s failed: %v", cell, err)
+			}
+			if formula == "" {
+				continue
+			}
+			value, err := f.CalcCellValue(sheetName, cell, importer.readOptions())
+			if err != nil {
+				importer.warn(Warning{RowIndex: row, Message: fmt.Sprintf("recalculate %s failed, keeping cached value: %v", cell, err)})
+				continue
+			}
+			fills.set(row, col-1, value)
+		}
+	}
+	return rows.Error()
+}
+
+// apply copies the replaced values into a row, growing it when needed
+func (m *cellFills) apply(row []string, rowIndex int) []string {
+	for col, value := range m.cells[rowIndex] {
+		row = growRow(row, col)
+		row[col] = value
+	}
+
+	for m.next < len(m.merges) && m.merges[m.next].firstRow <= rowIndex {
+		m.active = append(m.active, m.merges[m.next])
+		m.next++
+	}
+	m.active = slices.DeleteFunc(m.active, func(merge mergeFill) bool { return merge.lastRow < rowIndex })
+	for _, merge := range m.active {
+		row = growRow(row, merge.lastCol)
+		for col := merge.firstCol; col <= merge.lastCol; col++ {
+			row[col] = merge.value
+		}
+	}
+	return row
+}
+
+// growRow pads row so that col is a valid index
+func growRow(row []string, col int) []string {
+	if len(row) <= col {
+		row = append(row, make([]string, col+1-len(row))...)
+	}
+	return row
+}
+
+// filledRows applies cell fills while iterating, continuing past the last
+// stored row while fills still cover rows
+type filledRows struct {
+	rows      rowIterator
+	fills     *cellFills
+	row       int
+	stored    int
+	exhausted bool
+}
+
+func (m *filledRows) Next() bool {
+	if !m.exhausted && m.rows.Next() {
+		m.row++
+		return true
+	}
+	if !m.exhausted {
+		m.exhausted = true
+		m.stored = m.row
+	}
+	if m.fills.extends(m.row+1, m.stored) {
+		m.row++
+		return true
+	}
+	return false
+}
+
+func (m *filledRows) Columns() ([]string, error) {
+	var row []string
+	if !m.exhausted {
+		var err error
+		if row, err = m.rows.Columns(); err != nil {
+			return nil, err
+		}
+	}
+	return m.fills.apply(row, m.row), nil
+}
+
+func (m *filledRows) Close() error {
+	return m.rows.Close()
+}
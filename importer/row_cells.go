@@ -0,0 +1,73 @@
+package importer
+
+import (
+	"strconv"
+	"strings"
+)
+
+// RowCells looks up a row's cells by header, for RowHook and RowConverter code
+// that reads columns not mapped to a field:
+//
+//	RowHook: func(u *User, row []string, cols map[string]int) error {
+//		cells := importer.NewRowCells(row, cols)
+//		if age, ok := cells.Int("Age"); ok { ... }
+//		return nil
+//	},
+//
+// Every accessor returns false when the column is absent from the header, the
+// row is too short to hold it, the cell is empty or it does not parse.
+type RowCells struct {
+	row            []string
+	columnIndexMap map[string]int
+}
+
+// NewRowCells wraps the row and column index map a RowHook receives
+func NewRowCells(row []string, columnIndexMap map[string]int) RowCells {
+	return RowCells{row: row, columnIndexMap: columnIndexMap}
+}
+
+// String returns the trimmed cell text of header
+func (c RowCells) String(header string) (string, bool) {
+	idx, exists := c.columnIndexMap[header]
+	if !exists || idx >= len(c.row) {
+		return "", false
+	}
+	cell := strings.TrimSpace(c.row[idx])
+	return cell, cell != ""
+}
+
+// Int parses the cell of header as a base 10 integer
+func (c RowCells) Int(header string) (int, bool) {
+	cell, ok := c.String(header)
+	if !ok {
+		return 0, false
+	}
+	value, err := strconv.Atoi(cell)
+	if err != nil {
+		return 0, false
+	}
+	return value, true
+}
+
+// Float parses the cell of header as a float
+func (c RowCells) Float(header string) (float64, bool) {
+	cell, ok := c.String(header)
+	if !ok {
+		return 0, false
+	}
+	value, err := strconv.ParseFloat(cell, 64)
+	if err != nil {
+		return 0, false
+	}
+	return value, true
+}
+
+// Bool reads the cell of header with the default bool texts, e.g. true/false, 1/0, 是/否
+func (c RowCells) Bool(header string) (bool, bool) {
+	cell, ok := c.String(header)
+	if !ok {
+		return false, false
+	}
+	value, ok := defaultBoolValues[strings.ToLower(cell)]
+	return value, ok
+}
@@ -0,0 +1,263 @@
+package importer
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// gzipMagic is the gzip member header
+var gzipMagic = []byte{0x1F, 0x8B}
+
+// zipMagic starts every xlsx file, which is a zip archive
+var zipMagic = []byte("PK\x03\x04")
+
+// sniffLen is how much of the input is inspected to tell what it is
+const sniffLen = 512
+
+// workbook is an opened input, backed either by excelize or by the rows of a legacy .xls sheet
+type workbook struct {
+	file          *excelize.File
+	xlsRows       [][]string
+	xlsSheetNames []string
+	xlsSheetErr   error  // Configured sheet is missing, reported when rows are read
+	xlsData       []byte // Whole legacy file, for reading sheets other than the configured one
+}
+
+func (w *workbook) SheetNames() []string {
+	if w.file != nil {
+		return w.file.GetSheetList()
+	}
+	return w.xlsSheetNames
+}
+
+func (w *workbook) Close() error {
+	if w.file != nil {
+		return w.file.Close()
+	}
+	return nil
+}
+
+func (importer *ExcelImporter[T]) openWorkbookLocal(filePath string) (*workbook, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return importer.openWorkbook(file)
+}
+
+func (importer *ExcelImporter[T]) openWorkbookReader(r io.Reader) (*workbook, error) {
+	if rs, ok := r.(io.ReadSeeker); ok {
+		return importer.openWorkbook(rs)
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return importer.openWorkbook(bytes.NewReader(data))
+}
+
+func (importer *ExcelImporter[T]) openWorkbook(r io.ReadSeeker) (*workbook, error) {
+	compressed := importer.config.Compression == CompressionGzip
+	if importer.config.Compression == CompressionAuto {
+		var err error
+		if compressed, err = hasMagic(r, gzipMagic); err != nil {
+			return nil, err
+		}
+	}
+	if compressed {
+		zr, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, fmt.Errorf("gzip: %v", err)
+		}
+		defer zr.Close()
+		data, err := io.ReadAll(zr)
+		if err != nil {
+			return nil, fmt.Errorf("gzip: %v", err)
+		}
+		r = bytes.NewReader(data)
+	}
+
+	header, err := peek(r, sniffLen)
+	if err != nil {
+		return nil, err
+	}
+	isZip := bytes.HasPrefix(header, zipMagic)
+	if !isZip && !bytes.HasPrefix(header, xlsMagic) {
+		contentType := ""
+		if len(header) > 0 {
+			contentType = http.DetectContentType(header)
+		}
+		return nil, &NotSpreadsheetError{ContentType: contentType}
+	}
+
+	// Encrypted OOXML files share the OLE2 signature of legacy .xls files
+	legacy := false
+	if importer.config.InputPassword == "" {
+		var err error
+		if legacy, err = isLegacyXLS(r); err != nil {
+			return nil, err
+		}
+	}
+	if legacy {
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return nil, err
+		}
+		names, rows, err := readXLSRows(bytes.NewReader(data), importer.config.SheetName)
+		var notFound *SheetNotFoundError
+		if err != nil && !errors.As(err, &notFound) {
+			return nil, err
+		}
+		importer.emit(Event{Kind: EventFileOpened}, importStats{})
+		return &workbook{xlsRows: rows, xlsSheetNames: names, xlsSheetErr: err, xlsData: data}, nil
+	}
+
+	f, err := excelize.OpenReader(r, importer.openOptions())
+	if err != nil {
+		if isZip && !errors.Is(err, excelize.ErrWorkbookPassword) {
+			return nil, &CorruptFileError{Err: err}
+		}
+		return nil, err
+	}
+	importer.emit(Event{Kind: EventFileOpened}, importStats{})
+	return &workbook{file: f}, nil
+}
+
+func (importer *ExcelImporter[T]) readOptions() excelize.Options {
+	return excelize.Options{RawCellValue: importer.config.RawCellValues}
+}
+
+func (importer *ExcelImporter[T]) openOptions() excelize.Options {
+	return excelize.Options{Password: importer.config.InputPassword}
+}
+
+func (importer *ExcelImporter[T]) importWorkbook(wb *workbook) ([]T, error) {
+	if wb.file != nil {
+		return importer.importFromFile(wb.file)
+	}
+	if wb.xlsSheetErr != nil {
+		return nil, wb.xlsSheetErr
+	}
+	return importer.importXLSRows(wb.xlsRows)
+}
+
+// importWorkbookSheet imports one sheet of the workbook by name
+func (importer *ExcelImporter[T]) importWorkbookSheet(wb *workbook, sheetName string) ([]T, error) {
+	if wb.file != nil {
+		return importer.importSheet(wb.file, sheetName)
+	}
+	_, rows, err := readXLSRows(bytes.NewReader(wb.xlsData), sheetName)
+	if err != nil {
+		return nil, err
+	}
+	return importer.importXLSRows(rows)
+}
+
+// importXLSRows imports the rows of a legacy .xls sheet
+func (importer *ExcelImporter[T]) importXLSRows(rows [][]string) ([]T, error) {
+	importer, err := importer.withLookups(nil)
+	if err != nil {
+		return nil, err
+	}
+	if importer, err = importer.withComments(nil, ""); err != nil {
+		return nil, err
+	}
+	return importer.importRows(rows)
+}
+
+// peek returns up to n leading bytes of r, rewinding r afterwards
+func peek(r io.ReadSeeker, n int) ([]byte, error) {
+	header := make([]byte, n)
+	read, err := io.ReadFull(r, header)
+	if _, seekErr := r.Seek(0, io.SeekStart); seekErr != nil {
+		return nil, seekErr
+	}
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return nil, err
+	}
+	return header[:read], nil
+}
+
+// hasMagic reports whether r starts with magic, rewinding r afterwards
+func hasMagic(r io.ReadSeeker, magic []byte) (bool, error) {
+	header, err := peek(r, len(magic))
+	if err != nil {
+		return false, err
+	}
+	return bytes.Equal(header, magic), nil
+}
+
+// rowIterator is the subset of *excelize.Rows used when streaming
+type rowIterator interface {
+	Next() bool
+	Columns() ([]string, error)
+	Close() error
+}
+
+type excelizeRows struct {
+	*excelize.Rows
+	opts excelize.Options
+}
+
+func (r excelizeRows) Columns() ([]string, error) {
+	return r.Rows.Columns(r.opts)
+}
+
+type sliceRows struct {
+	rows [][]string
+	idx  int
+}
+
+func (r *sliceRows) Next() bool {
+	r.idx++
+	return r.idx <= len(r.rows)
+}
+
+func (r *sliceRows) Columns() ([]string, error) {
+	return r.rows[r.idx-1], nil
+}
+
+func (r *sliceRows) Close() error {
+	return nil
+}
+
+// offsetRows drops the cells left of StartColumn from every row
+type offsetRows struct {
+	rowIterator
+	offset int
+}
+
+func (r offsetRows) Columns() ([]string, error) {
+	row, err := r.rowIterator.Columns()
+	if err != nil || len(row) <= r.offset {
+		return nil, err
+	}
+	return row[r.offset:], nil
+}
+
+func (importer *ExcelImporter[T]) offsetRows(rows rowIterator) rowIterator {
+	if importer.config.StartColumn <= 1 {
+		return rows
+	}
+	return offsetRows{rowIterator: rows, offset: importer.config.StartColumn - 1}
+}
+
+// offsetRow is offsetRows for a single row
+func (importer *ExcelImporter[T]) offsetRow(row []string) []string {
+	offset := importer.config.StartColumn - 1
+	if offset <= 0 {
+		return row
+	}
+	if len(row) <= offset {
+		return nil
+	}
+	return row[offset:]
+}
@@ -0,0 +1,79 @@
+package importer
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/extrame/xls"
+)
+
+// xlsMagic is the OLE2 compound document signature used by legacy BIFF8 .xls files
+var xlsMagic = []byte{0xD0, 0xCF, 0x11, 0xE0}
+
+func isLegacyXLS(r io.ReadSeeker) (bool, error) {
+	return hasMagic(r, xlsMagic)
+}
+
+// readXLSRows reads all rows of a legacy .xls sheet along with the names of all
+// sheets. An empty sheetName selects the first sheet.
+func readXLSRows(r io.ReadSeeker, sheetName string) (names []string, rows [][]string, err error) {
+	// The BIFF reader panics on malformed input instead of returning errors
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("parse xls failed: %v", p)
+		}
+	}()
+
+	wb, err := xls.OpenReader(r, "utf-8")
+	if err != nil {
+		return nil, nil, err
+	}
+	if wb == nil {
+		// No Workbook stream: most likely an encrypted .xlsx
+		return nil, nil, fmt.Errorf("not a legacy xls workbook, the file may be password protected")
+	}
+	if wb.NumSheets() < 1 {
+		return nil, nil, ErrNoSheets
+	}
+
+	var sheet *xls.WorkSheet
+	for i := 0; i < wb.NumSheets(); i++ {
+		s := wb.GetSheet(i)
+		if s == nil {
+			continue
+		}
+		names = append(names, s.Name)
+		if sheet == nil && (sheetName == "" || s.Name == sheetName) {
+			sheet = s
+		}
+	}
+	if sheet == nil {
+		return nil, nil, &SheetNotFoundError{Sheet: sheetName, Available: names}
+	}
+
+	rows = make([][]string, int(sheet.MaxRow)+1)
+	for i := range rows {
+		rows[i] = readXLSRow(sheet, i)
+	}
+	return names, rows, nil
+}
+
+func readXLSRow(sheet *xls.WorkSheet, index int) (row []string) {
+	// WorkSheet.Row panics for rows without a record, which are simply empty
+	defer func() {
+		if recover() != nil {
+			row = nil
+		}
+	}()
+
+	r := sheet.Row(index)
+	row = make([]string, r.LastCol())
+	for col := range row {
+		row[col] = strings.TrimRight(r.Col(col), "\x00")
+	}
+	for len(row) > 0 && row[len(row)-1] == "" {
+		row = row[:len(row)-1]
+	}
+	return row
+}
@@ -0,0 +1,76 @@
+package impex_test
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/enterShuIoT/impex/exporter"
+	"github.com/enterShuIoT/impex/importer"
+)
+
+// RoundTripRow covers the field kinds both packages are expected to agree on
+type RoundTripRow struct {
+	Name    string            `excel:"名称"`
+	Count   int               `excel:"数量"`
+	Price   float64           `excel:"价格"`
+	Rate    *float64          `excel:"比率"`
+	Note    *string           `excel:"备注"`
+	Active  bool              `excel:"启用"`
+	Created time.Time         `excel:"创建时间"`
+	Tags    []string          `excel:"标签,json"`
+	Extra   map[string]string `excel:"extra"`
+}
+
+// roundTrip exports data with the exporter package and imports it back
+func roundTrip[T any](t *testing.T, config *exporter.ExcelExportConfig[T], data []T) []T {
+	t.Helper()
+
+	resp, err := exporter.NewExcelExporter(config).Export(data)
+	if err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	rows, err := importer.NewExcelImporter(&importer.ExcelImportConfig[T]{}).ImportReader(bytes.NewReader(resp.Content))
+	if err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+	return rows
+}
+
+func TestRoundTrip(t *testing.T) {
+	rate := 0.125
+	note := "备注"
+	data := []RoundTripRow{
+		{
+			Name:    "张三",
+			Count:   3,
+			Price:   0.1 + 0.2,
+			Rate:    &rate,
+			Note:    &note,
+			Active:  true,
+			Created: time.Date(2024, 3, 1, 8, 30, 15, 0, time.UTC),
+			Tags:    []string{"vip", "north"},
+			Extra:   map[string]string{"00:30": "100", "01:00": "200.5"},
+		},
+		{
+			Name:    "李四",
+			Count:   -7,
+			Price:   1234567.891,
+			Active:  false,
+			Created: time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC),
+			Extra:   map[string]string{"01:00": "300"},
+		},
+	}
+
+	rows := roundTrip(t, &exporter.ExcelExportConfig[RoundTripRow]{ExtraColumns: true}, data)
+	if !reflect.DeepEqual(rows, data) {
+		t.Errorf("Round trip mismatch:\n got  %+v\n want %+v", rows, data)
+	}
+
+	// Without ExtraColumns the map is not written as columns, so its keys are lost
+	rows = roundTrip(t, &exporter.ExcelExportConfig[RoundTripRow]{}, data)
+	if _, ok := rows[0].Extra["00:30"]; ok || rows[0].Name != "张三" {
+		t.Errorf("Expected the extra map keys to need ExtraColumns, got %+v", rows[0])
+	}
+}